@@ -5,4 +5,5 @@ go 1.13
 require (
 	github.com/hashicorp/go-cleanhttp v0.5.2
 	github.com/hashicorp/terraform-plugin-sdk v1.16.0
+	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
 )
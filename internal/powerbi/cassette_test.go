@@ -0,0 +1,78 @@
+package powerbi
+
+import (
+	"hash/crc32"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+)
+
+const (
+	testAccPlaceholderClientID          = "00000000-0000-0000-0000-000000000001"
+	testAccPlaceholderSecondaryUsername = "secondary.user@example.com"
+)
+
+// testAccRecorder records or replays the requests of the running acceptance test, it is nil when the test does not use a cassette
+var testAccRecorder *powerbiapi.Recorder
+
+// testAccRand generates the random values of the running acceptance test, it is nil when the test does not use a cassette
+var testAccRand *rand.Rand
+
+// testAccRandString generates a random string that is the same each time the running test is recorded or replayed
+func testAccRandString(length int) string {
+	if testAccRand == nil {
+		return acctest.RandString(length)
+	}
+	result := make([]byte, length)
+	for i := range result {
+		result[i] = acctest.CharSetAlpha[testAccRand.Intn(len(acctest.CharSetAlpha))]
+	}
+	return string(result)
+}
+
+// testAccCassetteMode is the mode set by POWERBI_CASSETTE_MODE, cassettes are not used when it is empty
+func testAccCassetteMode() powerbiapi.CassetteMode {
+	return powerbiapi.CassetteMode(os.Getenv("POWERBI_CASSETTE_MODE"))
+}
+
+// testAccUseCassette records the test to, or replays it from, testdata/cassettes/<test name>.json when
+// POWERBI_CASSETTE_MODE is set. Random values must be generated with testAccRandString after it is called
+// and the returned function deferred so the cassette is saved. Tests without a recorded cassette are skipped
+// when replaying
+func testAccUseCassette(t *testing.T) func() {
+	mode := testAccCassetteMode()
+	if mode == "" {
+		return func() {}
+	}
+
+	path := filepath.Join("testdata", "cassettes", t.Name()+".json")
+	if _, err := os.Stat(path); mode == powerbiapi.CassetteModeReplay && os.IsNotExist(err) {
+		return func() {}
+	}
+
+	recorder, err := powerbiapi.NewRecorder(path, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.AddReplacement(os.Getenv("POWERBI_CLIENT_ID"), testAccPlaceholderClientID)
+	recorder.AddReplacement(os.Getenv("POWERBI_SECONDARY_USERNAME"), testAccPlaceholderSecondaryUsername)
+	testAccRecorder = recorder
+
+	// random names must be the same when recording and replaying otherwise requests will not match
+	testAccRand = rand.New(rand.NewSource(int64(crc32.ChecksumIEEE([]byte(t.Name())))))
+
+	return func() {
+		testAccRecorder = nil
+		testAccRand = nil
+		if t.Failed() || t.Skipped() {
+			return
+		}
+		if err := recorder.Save(); err != nil {
+			t.Error(err)
+		}
+	}
+}
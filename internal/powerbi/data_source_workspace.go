@@ -22,14 +22,22 @@ func DataSourceWorkspace() *schema.Resource {
 				Computed:    true,
 				Description: "Capacity ID to be assigned to workspace.",
 			},
+			"profile_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The ID of the service principal profile the workspace is looked up as. Defaults to the provider `profile_id`.",
+			},
 		},
 	}
 }
 
 func dataSourceWorkspaceRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutRead)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
 	name := d.Get("name").(string)
-	workspace, err := client.GetGroupByName(name)
+	workspace, err := client.GetGroupByNameWithContext(ctx, name)
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package powerbi
 
 import (
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
@@ -14,8 +15,16 @@ func TestAccDataSourceWorkspace_basic(t *testing.T) {
 	workspaceSuffix := acctest.RandString(6)
 	var workspaceName = fmt.Sprintf("Acceptance Test Data Source Workspace %s - Basic", workspaceSuffix)
 
+	// the workspace is created before resource.Test so the acceptance test checks are needed here too
+	if os.Getenv(resource.TestEnvVar) == "" {
+		t.Skip(fmt.Sprintf("Acceptance tests skipped unless env '%s' set", resource.TestEnvVar))
+	}
+	testAccPreCheck(t)
+
 	provider := Provider()
-	provider.Configure(terraform.NewResourceConfigRaw(nil))
+	if err := provider.Configure(terraform.NewResourceConfigRaw(nil)); err != nil {
+		t.Fatal(err)
+	}
 	client := provider.Meta().(*powerbiapi.Client)
 	response, _ := client.CreateGroup(powerbiapi.CreateGroupRequest{
 		Name: workspaceName,
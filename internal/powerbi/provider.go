@@ -1,32 +1,128 @@
 package powerbi
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 // Provider represents the powerbi terraform provider
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"tenant_id": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("POWERBI_TENANT_ID", ""),
-				Description: "The Tenant ID for the tenant which contains the Azure Active Directory App Registration to use for performing Power BI REST API operations. This can also be sourced from the `POWERBI_TENANT_ID` Environment Variable",
+				Description: "The Tenant ID for the tenant which contains the Azure Active Directory App Registration to use for performing Power BI REST API operations. Required unless `use_msi`, `access_token` or `token_command` is used. This can also be sourced from the `POWERBI_TENANT_ID` Environment Variable",
 			},
 			"client_id": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("POWERBI_CLIENT_ID", ""),
-				Description: "Also called Application ID. The Client ID for the Azure Active Directory App Registration to use for performing Power BI REST API operations. This can also be sourced from the `POWERBI_CLIENT_ID` Environment Variable",
+				Description: "Also called Application ID. The Client ID for the Azure Active Directory App Registration to use for performing Power BI REST API operations. Required unless `use_msi`, `access_token` or `token_command` is used. When `use_msi` is enabled it optionally selects a user assigned identity. This can also be sourced from the `POWERBI_CLIENT_ID` Environment Variable",
 			},
 			"client_secret": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("POWERBI_CLIENT_SECRET", ""),
-				Description: "Also called Application Secret. The Client Secret for the Azure Active Directory App Registration to use for performing Power BI REST API operations. This can also be sourced from the `POWERBI_CLIENT_SECRET` Environment Variable",
+				Description: "Also called Application Secret. The Client Secret for the Azure Active Directory App Registration to use for performing Power BI REST API operations. Required unless another authentication method such as `client_certificate_path` or `use_oidc` is configured. This can also be sourced from the `POWERBI_CLIENT_SECRET` Environment Variable",
+			},
+			"client_certificate_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_CLIENT_CERTIFICATE_PATH", ""),
+				Description: "The path to a PFX or PEM certificate, including its RSA private key, registered against the Azure Active Directory App Registration. If provided will use client certificate authentication instead of `client_secret`. This can also be sourced from the `POWERBI_CLIENT_CERTIFICATE_PATH` Environment Variable",
+			},
+			"client_certificate_password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_CLIENT_CERTIFICATE_PASSWORD", ""),
+				Description: "The password protecting the certificate specified in `client_certificate_path`. This can also be sourced from the `POWERBI_CLIENT_CERTIFICATE_PASSWORD` Environment Variable",
+			},
+			"use_oidc": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_USE_OIDC", false),
+				Description: "If true, will use workload identity federation (OIDC) to exchange a federated token from the CI system for a Power BI token instead of using `client_secret`. This can also be sourced from the `POWERBI_USE_OIDC` Environment Variable",
+			},
+			"oidc_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_OIDC_TOKEN", ""),
+				Description: "A federated token to use when `use_oidc` is enabled. This can also be sourced from the `POWERBI_OIDC_TOKEN` Environment Variable",
+			},
+			"oidc_token_file_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"POWERBI_OIDC_TOKEN_FILE_PATH", "AZURE_FEDERATED_TOKEN_FILE"}, ""),
+				Description: "The path to a file containing a federated token to use when `use_oidc` is enabled. This can also be sourced from the `POWERBI_OIDC_TOKEN_FILE_PATH` or `AZURE_FEDERATED_TOKEN_FILE` Environment Variables",
+			},
+			"oidc_request_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"POWERBI_OIDC_REQUEST_URL", "ACTIONS_ID_TOKEN_REQUEST_URL", "SYSTEM_OIDCREQUESTURI"}, ""),
+				Description: "The URL to request a federated token from when `use_oidc` is enabled. This can also be sourced from the `POWERBI_OIDC_REQUEST_URL`, `ACTIONS_ID_TOKEN_REQUEST_URL` or `SYSTEM_OIDCREQUESTURI` Environment Variables",
+			},
+			"oidc_request_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"POWERBI_OIDC_REQUEST_TOKEN", "ACTIONS_ID_TOKEN_REQUEST_TOKEN", "SYSTEM_ACCESSTOKEN"}, ""),
+				Description: "The bearer token used to authenticate against `oidc_request_url`. This can also be sourced from the `POWERBI_OIDC_REQUEST_TOKEN`, `ACTIONS_ID_TOKEN_REQUEST_TOKEN` or `SYSTEM_ACCESSTOKEN` Environment Variables",
+			},
+			"oidc_azure_devops_service_connection_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_OIDC_AZURE_DEVOPS_SERVICE_CONNECTION_ID", ""),
+				Description: "The ID of the Azure DevOps service connection to request a federated token for. When set `oidc_request_url` is requested as an Azure DevOps OIDC request URI instead of a GitHub Actions token request URL. This can also be sourced from the `POWERBI_OIDC_AZURE_DEVOPS_SERVICE_CONNECTION_ID` Environment Variable",
+			},
+			"oidc_audience": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_OIDC_AUDIENCE", powerbiapi.DefaultFederatedTokenAudience),
+				Description: "The audience requested from `oidc_request_url`. Defaults to `api://AzureADTokenExchange`. This can also be sourced from the `POWERBI_OIDC_AUDIENCE` Environment Variable",
+			},
+			"use_msi": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_USE_MSI", false),
+				Description: "If true, will use the Azure managed identity of the machine running terraform instead of `client_secret`. Set `client_id` to use a user assigned identity. This can also be sourced from the `POWERBI_USE_MSI` Environment Variable",
+			},
+			"msi_endpoint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"POWERBI_MSI_ENDPOINT", "IDENTITY_ENDPOINT"}, ""),
+				Description: "The managed identity token endpoint to use when `use_msi` is enabled. Defaults to the App Service `IDENTITY_ENDPOINT` when available, otherwise the Azure Instance Metadata Service. This can also be sourced from the `POWERBI_MSI_ENDPOINT` Environment Variable",
+			},
+			"msi_identity_header": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_MSI_IDENTITY_HEADER", ""),
+				Description: "The secret sent to `msi_endpoint` when using the App Service identity protocol. Defaults to the App Service `IDENTITY_HEADER` when `msi_endpoint` is the App Service `IDENTITY_ENDPOINT`. This can also be sourced from the `POWERBI_MSI_IDENTITY_HEADER` Environment Variable",
+			},
+			"access_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_ACCESS_TOKEN", ""),
+				Description: "A Power BI access token that has already been acquired. The token is used as is and cannot be refreshed, so it must remain valid for the whole terraform run. This can also be sourced from the `POWERBI_ACCESS_TOKEN` Environment Variable",
+			},
+			"token_command": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_TOKEN_COMMAND", ""),
+				Description: "A command that prints a JSON access token, for example `az account get-access-token --resource https://analysis.windows.net/powerbi/api`. The output must contain `access_token` or `accessToken`, and may contain `expires_in`, `expires_on` or `expiresOn`. The command is run again when the token expires and is stopped if it runs for longer than 2 minutes. This can also be sourced from the `POWERBI_TOKEN_COMMAND` Environment Variable",
 			},
 			"username": {
 				Type:        schema.TypeString,
@@ -41,32 +137,287 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("POWERBI_PASSWORD", ""),
 				Description: "The password for the a Power BI user to use for performing Power BI REST API operations. If provided will use resource owner password credentials flow with delegate permissions. This can also be sourced from the `POWERBI_PASSWORD` Environment Variable",
 			},
+			"environment": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("POWERBI_ENVIRONMENT", "public"),
+				ValidateFunc: validation.StringInSlice(powerbiapi.Environments(), true),
+				Description:  "The Power BI cloud to connect to. Any value from `public`, `usgov`, `usgovhigh`, `dod` or `china`. Defaults to `public`. This can also be sourced from the `POWERBI_ENVIRONMENT` Environment Variable",
+			},
+			"api_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_API_URL", ""),
+				Description: "Overrides the Power BI REST API base URL determined by `environment`, for example `https://api.powerbi.com`. This can also be sourced from the `POWERBI_API_URL` Environment Variable",
+			},
+			"login_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_LOGIN_URL", ""),
+				Description: "Overrides the Azure Active Directory authority URL determined by `environment`, for example `https://login.microsoftonline.com`. This can also be sourced from the `POWERBI_LOGIN_URL` Environment Variable",
+			},
+			"resource_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_RESOURCE_URL", ""),
+				Description: "Overrides the resource that tokens are requested for determined by `environment`, for example `https://analysis.windows.net/powerbi/api`. This can also be sourced from the `POWERBI_RESOURCE_URL` Environment Variable",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("POWERBI_MAX_RETRIES", powerbiapi.DefaultRetryOptions().MaxRetries),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The number of times a throttled or intermittently failing request is retried. Defaults to `4`. This can also be sourced from the `POWERBI_MAX_RETRIES` Environment Variable",
+			},
+			"min_backoff": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("POWERBI_MIN_BACKOFF", powerbiapi.DefaultRetryOptions().MinBackoff.String()),
+				ValidateFunc: validateDuration,
+				Description:  "The delay before the first retry, doubling on each subsequent retry with added jitter. Retry-After headers from the service take precedence. Defaults to `1s`. This can also be sourced from the `POWERBI_MIN_BACKOFF` Environment Variable",
+			},
+			"max_backoff": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("POWERBI_MAX_BACKOFF", powerbiapi.DefaultRetryOptions().MaxBackoff.String()),
+				ValidateFunc: validateDuration,
+				Description:  "The maximum delay between retries. Defaults to `30s`. This can also be sourced from the `POWERBI_MAX_BACKOFF` Environment Variable",
+			},
+			"retryable_status_codes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "The HTTP status codes that are retried. Defaults to `429`, `500`, `502`, `503` and `504`",
+			},
+			"retryable_error_codes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The Power BI error codes that are retried regardless of HTTP status code. Defaults to `ServiceUnavailable`, `RequestTimeout` and `ServerBusy`",
+			},
+			"max_requests_per_minute": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("POWERBI_MAX_REQUESTS_PER_MINUTE", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The maximum number of requests sent to Power BI per minute, including retries. Use this to throttle the provider before Power BI throttles it. Defaults to `0` which is unlimited. This can also be sourced from the `POWERBI_MAX_REQUESTS_PER_MINUTE` Environment Variable",
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("POWERBI_MAX_CONCURRENT_REQUESTS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The maximum number of requests sent to Power BI at the same time, regardless of terraform parallelism. Defaults to `0` which is unlimited. This can also be sourced from the `POWERBI_MAX_CONCURRENT_REQUESTS` Environment Variable",
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_PROXY_URL", ""),
+				Description: "The proxy that requests to Power BI and the login endpoint are sent through, for example `http://proxy.example.com:8080`. Defaults to the proxy set by the `HTTPS_PROXY` and `NO_PROXY` Environment Variables. Managed identity endpoints and other loopback or link-local hosts are never proxied. This can also be sourced from the `POWERBI_PROXY_URL` Environment Variable",
+			},
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_CA_CERT_FILE", ""),
+				Description: "The path to a PEM file of root certificates to trust in addition to the system root certificates, such as the root certificate of a proxy that inspects TLS traffic. This can also be sourced from the `POWERBI_CA_CERT_FILE` Environment Variable",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_INSECURE_SKIP_VERIFY", false),
+				Description: "Disables verifying the certificates of Power BI and the login endpoint. This must only be used with test stand-ins of the service. This can also be sourced from the `POWERBI_INSECURE_SKIP_VERIFY` Environment Variable",
+			},
+			"tls_handshake_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("POWERBI_TLS_HANDSHAKE_TIMEOUT", powerbiapi.DefaultTLSHandshakeTimeout.String()),
+				ValidateFunc: validateDuration,
+				Description:  "How long to wait for a TLS handshake with Power BI and the login endpoint. Defaults to `1m0s`. This can also be sourced from the `POWERBI_TLS_HANDSHAKE_TIMEOUT` Environment Variable",
+			},
+			"cache_ttl": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("POWERBI_CACHE_TTL", "0s"),
+				ValidateFunc: validateDuration,
+				Description:  "How long workspace and capacity lookups are cached, so refreshing many workspaces lists them once rather than searching for each. Changes made by the provider invalidate the cache. Defaults to `0s` which disables caching. This can also be sourced from the `POWERBI_CACHE_TTL` Environment Variable",
+			},
+			"poll_interval": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("POWERBI_POLL_INTERVAL", powerbiapi.DefaultPollOptions().Interval.String()),
+				ValidateFunc: validateDuration,
+				Description:  "The delay between checks of long running operations such as imports. The delay increases by half after each check up to `max_poll_interval`. Defaults to `1s`. This can also be sourced from the `POWERBI_POLL_INTERVAL` Environment Variable",
+			},
+			"max_poll_interval": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("POWERBI_MAX_POLL_INTERVAL", powerbiapi.DefaultPollOptions().MaxInterval.String()),
+				ValidateFunc: validateDuration,
+				Description:  "The maximum delay between checks of long running operations. Set to the same value as `poll_interval` to check at a fixed interval. Defaults to `10s`. This can also be sourced from the `POWERBI_MAX_POLL_INTERVAL` Environment Variable",
+			},
+			"use_admin_apis": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_USE_ADMIN_APIS", false),
+				Description: "Updates workspaces through the Power BI admin APIs, so a Power BI administrator can rename workspaces they are not an admin of. Requires the `Tenant.ReadWrite.All` permission. This can also be sourced from the `POWERBI_USE_ADMIN_APIS` Environment Variable",
+			},
+			"profile_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_PROFILE_ID", ""),
+				Description: "The ID of the service principal profile requests are made as, so each tenant of a multi-tenant application can be isolated in its own profile. Resources can override this with their own `profile_id`. Defaults to making requests as the service principal itself. This can also be sourced from the `POWERBI_PROFILE_ID` Environment Variable",
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"powerbi_workspace":        ResourceWorkspace(),
-			"powerbi_pbix":             ResourcePBIX(),
-			"powerbi_refresh_schedule": ResourceRefreshSchedule(),
-			"powerbi_workspace_access": ResourceGroupUsers(),
-			"powerbi_dataset":          ResourceDataset(),
-			"powerbi_gatway":           ResourceGateways(),
+			"powerbi_workspace":                 ResourceWorkspace(),
+			"powerbi_pbix":                      ResourcePBIX(),
+			"powerbi_refresh_schedule":          ResourceRefreshSchedule(),
+			"powerbi_workspace_access":          ResourceGroupUsers(),
+			"powerbi_dataset":                   ResourceDataset(),
+			"powerbi_gatway":                    ResourceGateways(),
+			"powerbi_service_principal_profile": ResourceServicePrincipalProfile(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
 			"powerbi_workspace": DataSourceWorkspace(),
 		},
+	}
 
-		ConfigureFunc: providerConfigure,
+	p.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		client, err := providerConfigure(d, nil)
+		if err != nil {
+			return nil, err
+		}
+		client.StopContext = p.StopContext()
+		return client, nil
 	}
+
+	return p
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+// providerConfigure creates the client from the provider settings. configureOptions can adjust the client options
+// before the client is created, such as adding a recorder in tests
+func providerConfigure(d *schema.ResourceData, configureOptions func(*powerbiapi.ClientOptions)) (*powerbiapi.Client, error) {
+
+	endpoints, err := powerbiapi.GetEndpointsForEnvironment(d.Get("environment").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	retryOptions, err := readRetryOptions(d)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsHandshakeTimeout, err := time.ParseDuration(d.Get("tls_handshake_timeout").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	cacheTTL, err := time.ParseDuration(d.Get("cache_ttl").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	pollOptions, err := readPollOptions(d)
+	if err != nil {
+		return nil, err
+	}
+
+	options := powerbiapi.ClientOptions{
+		Endpoints: endpoints.WithOverrides(
+			d.Get("api_url").(string),
+			d.Get("login_url").(string),
+			d.Get("resource_url").(string),
+		),
+		Retry: retryOptions,
+		Throttle: powerbiapi.ThrottleOptions{
+			MaxRequestsPerMinute:  d.Get("max_requests_per_minute").(int),
+			MaxConcurrentRequests: d.Get("max_concurrent_requests").(int),
+		},
+		Transport: powerbiapi.TransportOptions{
+			ProxyURL:            d.Get("proxy_url").(string),
+			CACertFile:          d.Get("ca_cert_file").(string),
+			InsecureSkipVerify:  d.Get("insecure_skip_verify").(bool),
+			TLSHandshakeTimeout: tlsHandshakeTimeout,
+		},
+		ProfileID:    d.Get("profile_id").(string),
+		UseAdminAPIs: d.Get("use_admin_apis").(bool),
+		Cache: powerbiapi.CacheOptions{
+			TTL: cacheTTL,
+		},
+		Poll: pollOptions,
+	}
+	if configureOptions != nil {
+		configureOptions(&options)
+	}
+
+	if token, ok := d.GetOk("access_token"); ok {
+		return powerbiapi.NewClientWithAccessToken(options, token.(string))
+	}
+
+	if command, ok := d.GetOk("token_command"); ok {
+		return powerbiapi.NewClientWithTokenCommand(options, command.(string))
+	}
+
+	if d.Get("use_msi").(bool) {
+		return powerbiapi.NewClientWithManagedIdentityAuth(
+			options,
+			powerbiapi.ManagedIdentityOptions{
+				ClientID:       d.Get("client_id").(string),
+				Endpoint:       d.Get("msi_endpoint").(string),
+				IdentityHeader: msiIdentityHeader(d),
+			},
+		)
+	}
+
+	if d.Get("use_oidc").(bool) {
+		if err := requireAuthArguments(d, "OIDC", "tenant_id", "client_id"); err != nil {
+			return nil, err
+		}
+		return powerbiapi.NewClientWithFederatedTokenAuth(
+			options,
+			d.Get("tenant_id").(string),
+			d.Get("client_id").(string),
+			powerbiapi.FederatedTokenOptions{
+				Token:         d.Get("oidc_token").(string),
+				TokenFilePath: d.Get("oidc_token_file_path").(string),
+				RequestURL:    d.Get("oidc_request_url").(string),
+				RequestToken:  d.Get("oidc_request_token").(string),
+				Audience:      d.Get("oidc_audience").(string),
+
+				AzureDevOpsServiceConnectionID: d.Get("oidc_azure_devops_service_connection_id").(string),
+			},
+		)
+	}
+
+	if certificatePath, ok := d.GetOk("client_certificate_path"); ok {
+		if err := requireAuthArguments(d, "client certificate", "tenant_id", "client_id"); err != nil {
+			return nil, err
+		}
+		certificateData, err := ioutil.ReadFile(certificatePath.(string))
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read client certificate: %v", err)
+		}
+		return powerbiapi.NewClientWithClientCertificateAuth(
+			options,
+			d.Get("tenant_id").(string),
+			d.Get("client_id").(string),
+			certificateData,
+			d.Get("client_certificate_password").(string),
+		)
+	}
 
 	username, usernameOk := d.GetOk("username")
 	password, passwordOk := d.GetOk("password")
 
-	if usernameOk && passwordOk {
+	if usernameOk || passwordOk {
+		if err := requireAuthArguments(d, "password", "tenant_id", "client_id", "client_secret", "username", "password"); err != nil {
+			return nil, err
+		}
 		return powerbiapi.NewClientWithPasswordAuth(
+			options,
 			d.Get("tenant_id").(string),
 			d.Get("client_id").(string),
 			d.Get("client_secret").(string),
@@ -74,10 +425,93 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 			password.(string),
 		)
 	}
+
+	if err := requireAuthArguments(d, "client credential", "tenant_id", "client_id", "client_secret"); err != nil {
+		return nil, err
+	}
 	return powerbiapi.NewClientWithClientCredentialAuth(
+		options,
 		d.Get("tenant_id").(string),
 		d.Get("client_id").(string),
 		d.Get("client_secret").(string),
 	)
 
 }
+
+// requireAuthArguments returns an error naming the arguments the selected authentication method needs that are not set
+func requireAuthArguments(d *schema.ResourceData, method string, keys ...string) error {
+	var missing []string
+	for _, key := range keys {
+		if d.Get(key).(string) == "" {
+			missing = append(missing, "'"+key+"'")
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("Unable to configure %s authentication. Expected %s to be set", method, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// msiIdentityHeader is the configured msi_identity_header. The App Service IDENTITY_HEADER is only used when
+// msi_endpoint is the App Service IDENTITY_ENDPOINT so the secret is not sent to other endpoints
+func msiIdentityHeader(d *schema.ResourceData) string {
+	if identityHeader := d.Get("msi_identity_header").(string); identityHeader != "" {
+		return identityHeader
+	}
+	endpoint := d.Get("msi_endpoint").(string)
+	if endpoint != "" && endpoint == os.Getenv("IDENTITY_ENDPOINT") {
+		return os.Getenv("IDENTITY_HEADER")
+	}
+	return ""
+}
+
+func readRetryOptions(d *schema.ResourceData) (*powerbiapi.RetryOptions, error) {
+	retryOptions := powerbiapi.DefaultRetryOptions()
+	retryOptions.MaxRetries = d.Get("max_retries").(int)
+
+	minBackoff, err := time.ParseDuration(d.Get("min_backoff").(string))
+	if err != nil {
+		return nil, err
+	}
+	retryOptions.MinBackoff = minBackoff
+
+	maxBackoff, err := time.ParseDuration(d.Get("max_backoff").(string))
+	if err != nil {
+		return nil, err
+	}
+	retryOptions.MaxBackoff = maxBackoff
+
+	if statusCodes, ok := d.GetOk("retryable_status_codes"); ok {
+		retryOptions.RetryableStatusCodes = convertToIntSlice(statusCodes.([]interface{}))
+	}
+	if errorCodes, ok := d.GetOk("retryable_error_codes"); ok {
+		retryOptions.RetryableErrorCodes = convertToStringSlice(errorCodes.([]interface{}))
+	}
+
+	return &retryOptions, nil
+}
+
+func readPollOptions(d *schema.ResourceData) (powerbiapi.PollOptions, error) {
+	pollOptions := powerbiapi.DefaultPollOptions()
+
+	interval, err := time.ParseDuration(d.Get("poll_interval").(string))
+	if err != nil {
+		return pollOptions, err
+	}
+	pollOptions.Interval = interval
+
+	maxInterval, err := time.ParseDuration(d.Get("max_poll_interval").(string))
+	if err != nil {
+		return pollOptions, err
+	}
+	pollOptions.MaxInterval = maxInterval
+
+	return pollOptions, nil
+}
+
+func validateDuration(val interface{}, key string) (warns []string, errs []error) {
+	if _, err := time.ParseDuration(val.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("Expected argument '%s' to be a duration such as '1s' or '500ms'. Found '%v'", key, val))
+	}
+	return warns, errs
+}
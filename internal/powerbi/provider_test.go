@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapitest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
@@ -15,11 +18,78 @@ var testAccProvider *schema.Provider
 func init() {
 
 	testAccProvider = Provider()
+	// acceptance tests that use a cassette have their requests recorded or replayed
+	testAccProvider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		client, err := providerConfigure(d, func(options *powerbiapi.ClientOptions) {
+			options.Recorder = testAccRecorder
+		})
+		if err != nil {
+			return nil, err
+		}
+		client.StopContext = testAccProvider.StopContext()
+		return client, nil
+	}
 	testAccProviders = map[string]terraform.ResourceProvider{
 		"powerbi": testAccProvider,
 	}
 }
 
+// TestMain runs the acceptance tests against a fake Power BI service when POWERBI_FAKE_API is set. The
+// provider is pointed at the fake using the api_url and login_url settings so no tenant is required.
+// When POWERBI_CASSETTE_MODE is replay, tests with locally recorded cassettes are replayed and other acceptance
+// tests are skipped
+func TestMain(m *testing.M) {
+	var server *powerbiapitest.Server
+
+	if os.Getenv("POWERBI_FAKE_API") != "" {
+		server = powerbiapitest.NewServer()
+		setTestEnv(map[string]string{
+			"TF_ACC":                     "1",
+			"POWERBI_API_URL":            server.URL,
+			"POWERBI_LOGIN_URL":          server.URL,
+			"POWERBI_TENANT_ID":          "fake-tenant",
+			"POWERBI_CLIENT_ID":          "fake-client",
+			"POWERBI_CLIENT_SECRET":      "fake-secret",
+			"POWERBI_IS_PREMIUM":         "true",
+			"POWERBI_CAPACITY_ID":        server.AddCapacity("Fake Premium Capacity"),
+			"POWERBI_SECONDARY_USERNAME": testAccPlaceholderSecondaryUsername,
+		})
+	} else if testAccCassetteMode() == powerbiapi.CassetteModeReplay {
+		setTestEnv(map[string]string{
+			"TF_ACC":                     "1",
+			"POWERBI_TENANT_ID":          powerbiapi.PlaceholderTenantID,
+			"POWERBI_CLIENT_ID":          testAccPlaceholderClientID,
+			"POWERBI_CLIENT_SECRET":      "replayed-secret",
+			"POWERBI_SECONDARY_USERNAME": testAccPlaceholderSecondaryUsername,
+		})
+	}
+
+	code := m.Run()
+	if server != nil {
+		server.Close()
+	}
+	os.Exit(code)
+}
+
+// setTestEnv sets the environment for client credential authentication, credentials for other authentication
+// methods are cleared so they are not used instead
+func setTestEnv(env map[string]string) {
+	for _, name := range []string{
+		"POWERBI_CLIENT_CERTIFICATE_PATH",
+		"POWERBI_USE_OIDC",
+		"POWERBI_USE_MSI",
+		"POWERBI_ACCESS_TOKEN",
+		"POWERBI_TOKEN_COMMAND",
+		"POWERBI_USERNAME",
+		"POWERBI_PASSWORD",
+	} {
+		os.Unsetenv(name)
+	}
+	for name, value := range env {
+		os.Setenv(name, value)
+	}
+}
+
 func TestProvider_validate(t *testing.T) {
 	if err := Provider().InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
@@ -27,6 +97,10 @@ func TestProvider_validate(t *testing.T) {
 }
 
 func testAccPreCheck(t *testing.T) {
+	if testAccCassetteMode() == powerbiapi.CassetteModeReplay && testAccRecorder == nil {
+		t.Skip("No cassette is recorded for this test")
+	}
+
 	requiredEnvs := []string{
 		"POWERBI_TENANT_ID",
 		"POWERBI_CLIENT_ID",
@@ -38,3 +112,95 @@ func testAccPreCheck(t *testing.T) {
 		}
 	}
 }
+
+func TestMSIIdentityHeader_onlyUsesAppServiceHeaderForAppServiceEndpoint(t *testing.T) {
+	defer os.Setenv("IDENTITY_ENDPOINT", os.Getenv("IDENTITY_ENDPOINT"))
+	defer os.Setenv("IDENTITY_HEADER", os.Getenv("IDENTITY_HEADER"))
+	os.Setenv("IDENTITY_ENDPOINT", "http://127.0.0.1:41741/msi/token")
+	os.Setenv("IDENTITY_HEADER", "app-service-secret")
+
+	cases := []struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"msi_endpoint": "http://127.0.0.1:41741/msi/token"}, "app-service-secret"},
+		{map[string]interface{}{"msi_endpoint": "https://identity.example.com/token"}, ""},
+		{map[string]interface{}{"msi_endpoint": "https://identity.example.com/token", "msi_identity_header": "configured-secret"}, "configured-secret"},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, Provider().Schema, c.config)
+		if actual := msiIdentityHeader(d); actual != c.expected {
+			t.Fatalf("expected identity header %q for %v but got %q", c.expected, c.config, actual)
+		}
+	}
+}
+
+func TestProviderConfigure_requiresArgumentsForAuthMethod(t *testing.T) {
+	for _, name := range []string{
+		"POWERBI_TENANT_ID",
+		"POWERBI_CLIENT_ID",
+		"POWERBI_CLIENT_SECRET",
+		"POWERBI_CLIENT_CERTIFICATE_PATH",
+		"POWERBI_USE_OIDC",
+		"POWERBI_USE_MSI",
+		"POWERBI_ACCESS_TOKEN",
+		"POWERBI_TOKEN_COMMAND",
+		"POWERBI_USERNAME",
+		"POWERBI_PASSWORD",
+	} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Unsetenv(name)
+	}
+
+	cases := []struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		{
+			map[string]interface{}{"client_id": "client"},
+			"Unable to configure client credential authentication. Expected 'tenant_id', 'client_secret' to be set",
+		},
+		{
+			map[string]interface{}{"tenant_id": "tenant", "client_id": "client", "username": "user"},
+			"Unable to configure password authentication. Expected 'client_secret', 'password' to be set",
+		},
+		{
+			map[string]interface{}{"tenant_id": "tenant", "client_certificate_path": "certificate.pfx"},
+			"Unable to configure client certificate authentication. Expected 'client_id' to be set",
+		},
+		{
+			map[string]interface{}{"use_oidc": true, "oidc_token": "token"},
+			"Unable to configure OIDC authentication. Expected 'tenant_id', 'client_id' to be set",
+		},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, Provider().Schema, c.config)
+		_, err := providerConfigure(d, nil)
+		if err == nil || err.Error() != c.expected {
+			t.Fatalf("expected error %q for %v but got %v", c.expected, c.config, err)
+		}
+	}
+}
+
+func TestReadPollOptions(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{})
+	pollOptions, err := readPollOptions(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pollOptions != powerbiapi.DefaultPollOptions() {
+		t.Fatalf("expected the default poll options but got %+v", pollOptions)
+	}
+
+	d = schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"poll_interval":     "5s",
+		"max_poll_interval": "5s",
+	})
+	pollOptions, err = readPollOptions(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pollOptions.Interval != 5*time.Second || pollOptions.MaxInterval != 5*time.Second {
+		t.Fatalf("expected a fixed 5s poll interval but got %+v", pollOptions)
+	}
+}
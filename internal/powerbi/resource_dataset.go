@@ -42,10 +42,11 @@ func ResourceDataset() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"workspace_id": {
-				Type:        schema.TypeString,
-				Description: "Workspace ID in which the dataset will be added.",
-				Required:    true,
-				ForceNew:    true,
+				Type:             schema.TypeString,
+				Description:      "Workspace ID in which the dataset will be added. Set to `me`, or omit, to add the dataset to My workspace.",
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressMyWorkspaceDiff,
 			},
 			"name": {
 				Type:        schema.TypeString,
@@ -176,6 +177,7 @@ func ResourceDataset() *schema.Resource {
 					},
 				},
 			},
+			"profile_id": profileIDSchema(),
 		},
 	}
 }
@@ -200,12 +202,15 @@ func canonicalDefaultMode(value string) string {
 }
 
 func createDataset(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutCreate)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
 
 	groupID := d.Get("workspace_id").(string)
 	defaultRetentionPolicy := d.Get("default_retention_policy").(string)
 
-	resp, err := client.PostDatasetInGroup(groupID, defaultRetentionPolicy, powerbiapi.PostDatasetInGroupRequest{
+	resp, err := client.PostDatasetInGroupWithContext(ctx, groupID, defaultRetentionPolicy, powerbiapi.PostDatasetInGroupRequest{
 		Name:        d.Get("name").(string),
 		DefaultMode: canonicalDefaultMode(d.Get("default_mode").(string)),
 		Tables: genericMap(d.Get("table").(*schema.Set).List(), func(tableValues interface{}) powerbiapi.PostDatasetInGroupRequestTable {
@@ -254,12 +259,15 @@ func createDataset(d *schema.ResourceData, meta interface{}) error {
 }
 
 func readDataset(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutRead)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
 
 	groupID := d.Get("workspace_id").(string)
 
-	dataset, err := client.GetDatasetInGroup(groupID, d.Id())
-	if isHTTP404Error(err) {
+	dataset, err := client.GetDatasetInGroupWithContext(ctx, groupID, d.Id())
+	if powerbiapi.IsGoneError(err) {
 		d.SetId("")
 		return nil
 	}
@@ -274,8 +282,11 @@ func readDataset(d *schema.ResourceData, meta interface{}) error {
 }
 
 func updateDataset(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := operationContext(d, meta, schema.TimeoutUpdate)
+	defer cancel()
+
 	if d.HasChange("table") {
-		client := meta.(*powerbiapi.Client)
+		client := meta.(powerbiapi.API)
 
 		groupID := d.Get("workspace_id").(string)
 		datasetID := d.Id()
@@ -291,7 +302,7 @@ func updateDataset(d *schema.ResourceData, meta interface{}) error {
 
 		for _, tableToUpdateObj := range tablesToUpdate {
 			tableToUpdate := tableToUpdateObj.(map[string]interface{})
-			err := client.PutTableInGroup(groupID, datasetID, tableToUpdate["name"].(string), powerbiapi.PutTableInGroupRequest{
+			err := client.PutTableInGroupWithContext(ctx, groupID, datasetID, tableToUpdate["name"].(string), powerbiapi.PutTableInGroupRequest{
 
 				Name: tableToUpdate["name"].(string),
 
@@ -323,8 +334,11 @@ func updateDataset(d *schema.ResourceData, meta interface{}) error {
 }
 
 func deleteDataset(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutDelete)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
 
 	groupID := d.Get("workspace_id").(string)
-	return client.DeleteDatasetInGroup(groupID, d.Id())
+	return client.DeleteDatasetInGroupWithContext(ctx, groupID, d.Id())
 }
@@ -1,12 +1,16 @@
 package powerbi
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapifake"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
@@ -437,6 +441,61 @@ func TestAccDataset_forceNew(t *testing.T) {
 	})
 }
 
+func TestAccDataset_myWorkspace(t *testing.T) {
+	var datasetID string
+	datasetSuffix := acctest.RandString(6)
+	config := func(workspaceID string) string {
+		return fmt.Sprintf(`
+		resource "powerbi_dataset" "test" {
+			%s
+			default_mode = "push"
+			name = "Acceptance Test My Workspace Dataset %s"
+
+			table {
+				name = "entries"
+				column {
+					name = "entryId"
+					data_type = "string"
+				}
+			}
+		}
+		`, workspaceID, datasetSuffix)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if os.Getenv("POWERBI_USERNAME") == "" && os.Getenv("POWERBI_FAKE_API") == "" {
+				t.Skip("My workspace acceptance tests require user authentication, service principals do not have a My workspace")
+			}
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiDatasetDestroy,
+		Steps: []resource.TestStep{
+			// first step creates the dataset in My workspace by omitting the workspace
+			{
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("powerbi_dataset.test", "workspace_id"),
+					testDatsetExistsWithName("powerbi_dataset.test", ""),
+					testPushDataSuccessful("powerbi_dataset.test", "entries", []map[string]interface{}{
+						{"entryId": "abc"},
+					}),
+					set("powerbi_dataset.test", "id", &datasetID),
+				),
+			},
+			// second step refers to My workspace as me which does not replace the dataset
+			{
+				Config: config(`workspace_id = "me"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("powerbi_dataset.test", "id", &datasetID),
+					testDatsetExistsWithName("powerbi_dataset.test", ""),
+				),
+			},
+		},
+	})
+}
+
 func testDatsetExistsWithName(rn string, expectedName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -506,3 +565,73 @@ func testCheckResourceAttrNotEquals(resourceName string, attrName string, test *
 		return nil
 	}
 }
+
+func TestUpdateDataset_putsOnlyChangedTables(t *testing.T) {
+	table := func(name string, columnType string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":   name,
+			"column": []interface{}{map[string]interface{}{"name": "column", "data_type": columnType}},
+		}
+	}
+
+	config := func(tables ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"workspace_id": "workspace",
+			"name":         "dataset",
+			"default_mode": "push",
+			"table":        tables,
+		}
+	}
+
+	datasetResource := ResourceDataset()
+	original := schema.TestResourceDataRaw(t, datasetResource.Schema, config(table("changed", "string"), table("unchanged", "string")))
+	original.SetId("dataset-id")
+
+	diff, err := datasetResource.Diff(original.State(), terraform.NewResourceConfigRaw(config(table("changed", "int64"), table("unchanged", "string"))), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := schema.InternalMap(datasetResource.Schema).Data(original.State(), diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var putTables []powerbiapi.PutTableInGroupRequest
+	client := &powerbiapifake.Client{
+		PutTableInGroupFunc: func(ctx context.Context, groupID string, datasetID string, tableName string, request powerbiapi.PutTableInGroupRequest) error {
+			putTables = append(putTables, request)
+			return nil
+		},
+		GetDatasetInGroupFunc: func(ctx context.Context, groupID string, datasetID string) (*powerbiapi.GetDatasetInGroupResponse, error) {
+			return &powerbiapi.GetDatasetInGroupResponse{ID: datasetID, Name: "dataset"}, nil
+		},
+	}
+
+	if err := updateDataset(d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(putTables) != 1 || putTables[0].Name != "changed" || putTables[0].Columns[0].DataType != "int64" {
+		t.Fatalf("expected only the changed table to be updated but got %+v", putTables)
+	}
+}
+
+func testAccCheckPowerbiDatasetDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*powerbiapi.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "powerbi_dataset" {
+			continue
+		}
+
+		_, err := client.GetDatasetInGroup(rs.Primary.Attributes["workspace_id"], rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("dataset '%s' still exists", rs.Primary.ID)
+		}
+		if !powerbiapi.IsNotFoundError(err) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -9,42 +9,59 @@ import (
 // ResourceGateways represent managment of DataSources on On-premises gatways.
 func ResourceGateways() *schema.Resource {
 	return &schema.Resource{
-		Read: getGateway,
+		Create: createGateway,
+		Read:   getGateway,
+		// gateways are registered on premises so are only removed from state
+		Delete: schema.RemoveFromState,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceGatewayV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeGatewayStateV0,
+			},
+		},
 
 		Schema: map[string]*schema.Schema{
-			"gatewayId": {
+			"gateway_id": {
 				Type:        schema.TypeString,
 				Required:    true,
+				ForceNew:    true,
 				Description: "The gateway ID. When using a gateway cluster, the gateway ID refers to the primary (first) gateway in the cluster. In such cases, gateway ID is similar to gateway cluster ID.",
 			},
 			"gateway": {
 				Type:        schema.TypeSet,
 				Description: "Gatway Definition",
-				Optional:    true,
+				Computed:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"id": {
 							Type:        schema.TypeString,
 							Description: "The gateway ID. When using a gateway cluster, the gateway ID refers to the primary (first) gateway in the cluster and is similar to the gateway cluster ID.",
+							Computed:    true,
 						},
 						"name": {
 							Type:        schema.TypeString,
 							Description: "The gateway name",
+							Computed:    true,
 						},
 						"type": {
 							Type:        schema.TypeString,
 							Description: "The gateway type",
+							Computed:    true,
 						},
-						"gatewayStatus": {
+						"gateway_status": {
 							Type:        schema.TypeString,
 							Description: "The gateway connectivity status",
+							Computed:    true,
 						},
-						"gatewayAnnotation": {
+						"gateway_annotation": {
 							Type:        schema.TypeString,
 							Description: "Gateway metadata in JSON format",
+							Computed:    true,
 						},
 					},
 				},
@@ -67,10 +84,22 @@ func ResourceGateways() *schema.Resource {
 	}
 }
 
+func createGateway(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(d.Get("gateway_id").(string))
+	return getGateway(d, meta)
+}
+
 func getGateway(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
-	gatewayId := d.Get("gatewayId").(string)
-	gateway, err := client.GetGateway(gatewayId)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutRead)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
+	gatewayId := d.Get("gateway_id").(string)
+	gateway, err := client.GetGatewayWithContext(ctx, gatewayId)
+	if powerbiapi.IsGoneError(err) {
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
@@ -79,35 +108,84 @@ func getGateway(d *schema.ResourceData, meta interface{}) error {
 		d.SetId("")
 	} else {
 		d.SetId(gateway.ID)
-		d.Set("name", gateway.Name)
-		d.Set("gatewayAnnotation", gateway.GatewayAnnotation)
-		d.Set("type", gateway.Type)
-		d.Set("gatewayStatus", gateway.GatewayStatus)
-		//todo
-		/*		if gateway.PublicKey {
-					d.Set("exponent", gateway.PublicKey.exponent)
-					d.Set("modulus", gateway.PublicKey.modulus)
-				} else {
-					d.Set("exponent", "")
-					d.Set("modulus", "")
-				}
-		*/
+		err = d.Set("gateway_id", gateway.ID)
+		if err != nil {
+			return err
+		}
+		err = d.Set("gateway", []interface{}{
+			map[string]interface{}{
+				"id":                 gateway.ID,
+				"name":               gateway.Name,
+				"type":               gateway.Type,
+				"gateway_status":     gateway.GatewayStatus,
+				"gateway_annotation": gateway.GatewayAnnotation,
+			},
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// todo
-func getGateways(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
-
-	gateways, err := client.GetGateways()
-	if err != nil {
-		return err
+// resourceGatewayV0 is the schema before attributes were renamed to snake case
+func resourceGatewayV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"gatewayId": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"gateway": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"gatewayStatus": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"gatewayAnnotation": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
 	}
+}
 
-	if gateways == nil {
+// upgradeGatewayStateV0 renames the camel case attributes of version 0 to snake case
+func upgradeGatewayStateV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	renameStateKey(rawState, "gatewayId", "gateway_id")
+	if gateways, ok := rawState["gateway"].([]interface{}); ok {
+		for _, gateway := range gateways {
+			if gateway, ok := gateway.(map[string]interface{}); ok {
+				renameStateKey(gateway, "gatewayStatus", "gateway_status")
+				renameStateKey(gateway, "gatewayAnnotation", "gateway_annotation")
+			}
+		}
+	}
+	return rawState, nil
+}
 
+func renameStateKey(state map[string]interface{}, oldKey string, newKey string) {
+	if value, ok := state[oldKey]; ok {
+		state[newKey] = value
+		delete(state, oldKey)
 	}
-	return nil
 }
@@ -0,0 +1,86 @@
+package powerbi
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapifake"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestGetGateway_setsGatewayAttributes(t *testing.T) {
+	client := &powerbiapifake.Client{
+		GetGatewayFunc: func(ctx context.Context, gatewayId string) (*powerbiapi.GetGatewaysResponseItem, error) {
+			return &powerbiapi.GetGatewaysResponseItem{
+				ID:                gatewayId,
+				Name:              "gateway name",
+				Type:              "Resource",
+				GatewayStatus:     "Live",
+				GatewayAnnotation: "{}",
+			}, nil
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceGateways().Schema, map[string]interface{}{
+		"gateway_id": "gateway",
+	})
+
+	if err := createGateway(d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Id() != "gateway" {
+		t.Fatalf("expected id to be the gateway ID but got %q", d.Id())
+	}
+	gateways := d.Get("gateway").(*schema.Set).List()
+	expected := map[string]interface{}{
+		"id":                 "gateway",
+		"name":               "gateway name",
+		"type":               "Resource",
+		"gateway_status":     "Live",
+		"gateway_annotation": "{}",
+	}
+	if len(gateways) != 1 || !reflect.DeepEqual(gateways[0], expected) {
+		t.Fatalf("expected gateway %v but got %v", expected, gateways)
+	}
+}
+
+func TestUpgradeGatewayStateV0_renamesAttributes(t *testing.T) {
+	state := map[string]interface{}{
+		"id":        "gateway",
+		"gatewayId": "gateway",
+		"gateway": []interface{}{
+			map[string]interface{}{
+				"id":                "gateway",
+				"name":              "gateway name",
+				"type":              "Resource",
+				"gatewayStatus":     "Live",
+				"gatewayAnnotation": "{}",
+			},
+		},
+	}
+
+	actual, err := upgradeGatewayStateV0(state, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"id":         "gateway",
+		"gateway_id": "gateway",
+		"gateway": []interface{}{
+			map[string]interface{}{
+				"id":                 "gateway",
+				"name":               "gateway name",
+				"type":               "Resource",
+				"gateway_status":     "Live",
+				"gateway_annotation": "{}",
+			},
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected upgraded state %v but got %v", expected, actual)
+	}
+}
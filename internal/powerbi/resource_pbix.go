@@ -1,8 +1,8 @@
 package powerbi
 
 import (
+	"context"
 	"fmt"
-	"io"
 	"os"
 	"time"
 
@@ -23,10 +23,11 @@ func ResourcePBIX() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"workspace_id": {
-				Type:        schema.TypeString,
-				Description: "Workspace ID in which the PBIX will be added.",
-				Required:    true,
-				ForceNew:    true,
+				Type:             schema.TypeString,
+				Description:      "Workspace ID in which the PBIX will be added. Set to `me`, or omit, to add the PBIX to My workspace.",
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressMyWorkspaceDiff,
 			},
 			"name": {
 				Type:        schema.TypeString,
@@ -36,7 +37,7 @@ func ResourcePBIX() *schema.Resource {
 			},
 			"source": {
 				Type:        schema.TypeString,
-				Description: "An absolute path to a PBIX file on the local system.",
+				Description: "An absolute path to a PBIX file on the local system. Files larger than 1 GB are uploaded through a temporary upload location, which requires the workspace to be on a Premium capacity.",
 				Required:    true,
 			},
 			"source_hash": {
@@ -134,6 +135,7 @@ func ResourcePBIX() *schema.Resource {
 					},
 				},
 			},
+			"profile_id": profileIDSchema(),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Default: schema.DefaultTimeout(5 * time.Minute),
@@ -141,37 +143,40 @@ func ResourcePBIX() *schema.Resource {
 	}
 }
 
-func openContentReader(d *schema.ResourceData) (io.Reader, error) {
+func openContentReader(d *schema.ResourceData) (*os.File, error) {
 	filepath := d.Get("source").(string)
 	return os.Open(filepath)
 }
 
 func createPBIX(d *schema.ResourceData, meta interface{}) error {
 
+	// uploads can take longer than the timeout, which only applies to waiting for the import
+	ctx := resourceContext(d, meta)
+
 	d.Partial(true)
 
-	err := createImport(d, meta)
+	err := createImport(ctx, d, meta)
 	if err != nil {
 		return err
 	}
 
-	err = readImport(d, meta, d.Timeout(schema.TimeoutCreate))
+	err = readImport(ctx, d, meta, d.Timeout(schema.TimeoutCreate))
 	if err != nil {
 		return err
 	}
 
-	err = setPBIXParameters(d, meta)
+	err = setPBIXParameters(ctx, d, meta)
 	if err != nil {
 		return err
 	}
 
-	err = setPBIXDatasources(d, meta)
+	err = setPBIXDatasources(ctx, d, meta)
 	if err != nil {
 		return err
 	}
 
 	if _, ok := d.GetOk("rebind_dataset_id"); ok {
-		err = rebindPBIXDataset(d, meta)
+		err = rebindPBIXDataset(ctx, d, meta)
 		if err != nil {
 			return err
 		}
@@ -185,8 +190,11 @@ func createPBIX(d *schema.ResourceData, meta interface{}) error {
 
 func readPBIX(d *schema.ResourceData, meta interface{}) error {
 
-	err := readImport(d, meta, d.Timeout(schema.TimeoutRead))
-	if isHTTP404Error(err) {
+	ctx, cancel := operationContext(d, meta, schema.TimeoutRead)
+	defer cancel()
+
+	err := readImport(ctx, d, meta, d.Timeout(schema.TimeoutRead))
+	if powerbiapi.IsGoneError(err) {
 		d.SetId("")
 		return nil
 	}
@@ -194,12 +202,12 @@ func readPBIX(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	err = readPBIXParameters(d, meta)
+	err = readPBIXParameters(ctx, d, meta)
 	if err != nil {
 		return err
 	}
 
-	err = readPBIXDatasources(d, meta)
+	err = readPBIXDatasources(ctx, d, meta)
 	if err != nil {
 		return err
 	}
@@ -208,37 +216,40 @@ func readPBIX(d *schema.ResourceData, meta interface{}) error {
 }
 
 func updatePBIX(d *schema.ResourceData, meta interface{}) error {
+	// uploads can take longer than the timeout, which only applies to waiting for the import
+	ctx := resourceContext(d, meta)
+
 	if d.HasChange("source") || d.HasChange("source_hash") || d.HasChange("datasource") {
 
 		d.Partial(true)
 
 		// Imports do not update rebinded datasets, so we unbind before doing the import
-		err := unbindPBIXDataset(d, meta)
+		err := unbindPBIXDataset(ctx, d, meta)
 		if err != nil {
 			return err
 		}
 
-		err = createImport(d, meta)
+		err = createImport(ctx, d, meta)
 		if err != nil {
 			return err
 		}
 
-		err = readImport(d, meta, d.Timeout(schema.TimeoutUpdate))
+		err = readImport(ctx, d, meta, d.Timeout(schema.TimeoutUpdate))
 		if err != nil {
 			return err
 		}
 
-		err = setPBIXParameters(d, meta)
+		err = setPBIXParameters(ctx, d, meta)
 		if err != nil {
 			return err
 		}
 
-		err = setPBIXDatasources(d, meta)
+		err = setPBIXDatasources(ctx, d, meta)
 		if err != nil {
 			return err
 		}
 
-		err = rebindPBIXDataset(d, meta)
+		err = rebindPBIXDataset(ctx, d, meta)
 		if err != nil {
 			return err
 		}
@@ -249,19 +260,19 @@ func updatePBIX(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	if d.HasChange("rebind_dataset_id") {
-		err := unbindPBIXDataset(d, meta)
+		err := unbindPBIXDataset(ctx, d, meta)
 		if err != nil {
 			return err
 		}
 
-		err = rebindPBIXDataset(d, meta)
+		err = rebindPBIXDataset(ctx, d, meta)
 		if err != nil {
 			return err
 		}
 	}
 
 	if d.HasChange("parameter") {
-		err := setPBIXParameters(d, meta)
+		err := setPBIXParameters(ctx, d, meta)
 		if err != nil {
 			return err
 		}
@@ -271,20 +282,24 @@ func updatePBIX(d *schema.ResourceData, meta interface{}) error {
 }
 
 func deletePBIX(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutDelete)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
 
 	groupID := d.Get("workspace_id").(string)
 
+	// reports are deleted along with the dataset they are bound to, so may already be gone
 	if reportID, reportIDOk := d.GetOk("report_id"); reportIDOk {
-		err := client.DeleteReportInGroup(groupID, reportID.(string))
-		if err != nil {
+		err := client.DeleteReportInGroupWithContext(ctx, groupID, reportID.(string))
+		if err != nil && !powerbiapi.IsGoneError(err) {
 			return err
 		}
 	}
 
 	if datasetID, datasetIDOk := d.GetOk("dataset_id"); datasetIDOk {
-		err := client.DeleteDatasetInGroup(groupID, datasetID.(string))
-		if err != nil {
+		err := client.DeleteDatasetInGroupWithContext(ctx, groupID, datasetID.(string))
+		if err != nil && !powerbiapi.IsGoneError(err) {
 			return err
 		}
 	}
@@ -292,15 +307,28 @@ func deletePBIX(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func createImport(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+func createImport(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(powerbiapi.API)
 
 	reader, err := openContentReader(d)
 	if err != nil {
 		return err
 	}
+	defer reader.Close()
+
+	info, err := reader.Stat()
+	if err != nil {
+		return err
+	}
+
+	// files over 1 GB can only be imported by first uploading them to a temporary upload location
+	postImport := client.PostImportInGroupWithContext
+	if info.Size() > powerbiapi.LargeImportThreshold {
+		postImport = client.PostLargeImportInGroupWithContext
+	}
 
-	resp, err := client.PostImportInGroup(
+	resp, err := postImport(
+		ctx,
 		d.Get("workspace_id").(string),
 		d.Get("name").(string),
 		"CreateOrOverwrite",
@@ -319,12 +347,15 @@ func createImport(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func readImport(d *schema.ResourceData, meta interface{}, timeoutForSuccessfulImport time.Duration) error {
-	client := meta.(*powerbiapi.Client)
+func readImport(ctx context.Context, d *schema.ResourceData, meta interface{}, timeout time.Duration) error {
+	client := meta.(powerbiapi.API)
 	id := d.Id()
 	groupID := d.Get("workspace_id").(string)
 
-	im, err := client.WaitForImportInGroupToSucceed(groupID, id, timeoutForSuccessfulImport)
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	im, err := client.WaitForImportInGroupToSucceedWithContext(waitCtx, groupID, id)
 	if err != nil {
 		return err
 	}
@@ -340,7 +371,7 @@ func readImport(d *schema.ResourceData, meta interface{}, timeoutForSuccessfulIm
 			d.SetPartial("report_id")
 			d.Set("report_id", im.Reports[0].ID)
 
-			report, err := client.GetReportInGroup(groupID, im.Reports[0].ID)
+			report, err := client.GetReportInGroupWithContext(ctx, groupID, im.Reports[0].ID)
 			if err != nil {
 				return err
 			}
@@ -356,9 +387,9 @@ func readImport(d *schema.ResourceData, meta interface{}, timeoutForSuccessfulIm
 	return nil
 }
 
-func setPBIXParameters(d *schema.ResourceData, meta interface{}) error {
+func setPBIXParameters(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 
-	client := meta.(*powerbiapi.Client)
+	client := meta.(powerbiapi.API)
 	parameter := d.Get("parameter").(*schema.Set)
 	datasetID, datasetOk := d.GetOk("dataset_id")
 	groupID := d.Get("workspace_id").(string)
@@ -379,7 +410,7 @@ func setPBIXParameters(d *schema.ResourceData, meta interface{}) error {
 				})
 			}
 
-			err := client.UpdateParametersInGroup(groupID, datasetID.(string), updateParameterRequest)
+			err := client.UpdateParametersInGroupWithContext(ctx, groupID, datasetID.(string), updateParameterRequest)
 			if err != nil {
 				return err
 			}
@@ -391,9 +422,9 @@ func setPBIXParameters(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func readPBIXParameters(d *schema.ResourceData, meta interface{}) error {
+func readPBIXParameters(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 
-	client := meta.(*powerbiapi.Client)
+	client := meta.(powerbiapi.API)
 
 	groupID := d.Get("workspace_id").(string)
 	datasetID, datasetOK := d.GetOk("dataset_id")
@@ -404,7 +435,7 @@ func readPBIXParameters(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	apiParameters, err := client.GetParametersInGroup(groupID, datasetID.(string))
+	apiParameters, err := client.GetParametersInGroupWithContext(ctx, groupID, datasetID.(string))
 	if err != nil {
 		return err
 	}
@@ -423,9 +454,9 @@ func readPBIXParameters(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func setPBIXDatasources(d *schema.ResourceData, meta interface{}) error {
+func setPBIXDatasources(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 
-	client := meta.(*powerbiapi.Client)
+	client := meta.(powerbiapi.API)
 	datasources := d.Get("datasource").(*schema.Set)
 	datasetID, datasetOk := d.GetOk("dataset_id")
 	groupID := d.Get("workspace_id").(string)
@@ -458,7 +489,7 @@ func setPBIXDatasources(d *schema.ResourceData, meta interface{}) error {
 				})
 			}
 
-			err := client.UpdateDatasourcesInGroup(groupID, datasetID.(string), updateDatasourcesRequest)
+			err := client.UpdateDatasourcesInGroupWithContext(ctx, groupID, datasetID.(string), updateDatasourcesRequest)
 			if err != nil {
 				return err
 			}
@@ -470,9 +501,9 @@ func setPBIXDatasources(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func readPBIXDatasources(d *schema.ResourceData, meta interface{}) error {
+func readPBIXDatasources(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 
-	client := meta.(*powerbiapi.Client)
+	client := meta.(powerbiapi.API)
 
 	datasetID, datasetOk := d.GetOk("dataset_id")
 	groupID := d.Get("workspace_id").(string)
@@ -483,7 +514,7 @@ func readPBIXDatasources(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	apiDatasources, err := client.GetDatasourcesInGroup(groupID, datasetID.(string))
+	apiDatasources, err := client.GetDatasourcesInGroupWithContext(ctx, groupID, datasetID.(string))
 	if err != nil {
 		return err
 	}
@@ -519,8 +550,8 @@ func readPBIXDatasources(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func rebindPBIXDataset(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+func rebindPBIXDataset(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(powerbiapi.API)
 
 	groupID := d.Get("workspace_id").(string)
 	rebindDatasetID, rebindDatasetOk := d.GetOk("rebind_dataset_id")
@@ -531,13 +562,13 @@ func rebindPBIXDataset(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	return client.RebindReportInGroup(groupID, reportID.(string), powerbiapi.RebindReportInGroupRequest{
+	return client.RebindReportInGroupWithContext(ctx, groupID, reportID.(string), powerbiapi.RebindReportInGroupRequest{
 		DatasetID: rebindDatasetID.(string),
 	})
 }
 
-func unbindPBIXDataset(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+func unbindPBIXDataset(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(powerbiapi.API)
 
 	groupID := d.Get("workspace_id").(string)
 	originalDatasetID, datasetOk := d.GetOk("report_original_dataset_id")
@@ -551,7 +582,7 @@ func unbindPBIXDataset(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	return client.RebindReportInGroup(groupID, reportID.(string), powerbiapi.RebindReportInGroupRequest{
+	return client.RebindReportInGroupWithContext(ctx, groupID, reportID.(string), powerbiapi.RebindReportInGroupRequest{
 		DatasetID: originalDatasetID.(string),
 	})
 }
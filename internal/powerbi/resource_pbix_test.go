@@ -1,29 +1,34 @@
 package powerbi
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"math/rand"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/codecutout/terraform-provider-powerbi/internal/pbixrewriter"
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapifake"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
 func TestAccPBIX_basic(t *testing.T) {
+	defer testAccUseCassette(t)()
 	var updatedTime time.Time
 	pbixLocation := TempFileName("", ".pbix")
 	pbixLocationTfFriendly := strings.ReplaceAll(pbixLocation, "\\", "\\\\")
-	workspaceSuffix := acctest.RandString(6)
+	workspaceSuffix := testAccRandString(6)
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -102,13 +107,14 @@ func TestAccPBIX_basic(t *testing.T) {
 }
 
 func TestAccPBIX_external_dataset_report(t *testing.T) {
+	defer testAccUseCassette(t)()
 	datasetPbixLocation := TempFileName("", ".pbix")
 	datasetPbixLocationTfFriendly := strings.ReplaceAll(datasetPbixLocation, "\\", "\\\\")
 
 	reportPbixLocation := TempFileName("", ".pbix")
 	reportPbixLocationTfFriendly := strings.ReplaceAll(reportPbixLocation, "\\", "\\\\")
 
-	workspaceSuffix := acctest.RandString(6)
+	workspaceSuffix := testAccRandString(6)
 	var datasetID string
 
 	resource.Test(t, resource.TestCase{
@@ -252,13 +258,14 @@ func TestAccPBIX_external_dataset_report(t *testing.T) {
 }
 
 func TestAccPBIX_rebind_dataset(t *testing.T) {
+	defer testAccUseCassette(t)()
 	datasetPbixLocation := TempFileName("dataset_", ".pbix")
 	datasetPbixLocationTfFriendly := strings.ReplaceAll(datasetPbixLocation, "\\", "\\\\")
 
 	reportPbixLocation := TempFileName("report_", ".pbix")
 	reportPbixLocationTfFriendly := strings.ReplaceAll(reportPbixLocation, "\\", "\\\\")
 
-	workspaceSuffix := acctest.RandString(6)
+	workspaceSuffix := testAccRandString(6)
 	var report_datasetID string
 	var dataset_datasetID string
 	var dataset2_datasetID string
@@ -423,13 +430,14 @@ func TestAccPBIX_rebind_dataset(t *testing.T) {
 }
 
 func TestAccPBIX_rebind_dataset_with_update(t *testing.T) {
+	defer testAccUseCassette(t)()
 	datasetPbixLocation := TempFileName("dataset_", ".pbix")
 	datasetPbixLocationTfFriendly := strings.ReplaceAll(datasetPbixLocation, "\\", "\\\\")
 
 	reportPbixLocation := TempFileName("report_", ".pbix")
 	reportPbixLocationTfFriendly := strings.ReplaceAll(reportPbixLocation, "\\", "\\\\")
 
-	workspaceSuffix := acctest.RandString(6)
+	workspaceSuffix := testAccRandString(6)
 	var dataset_datasetID string
 
 	resource.Test(t, resource.TestCase{
@@ -502,13 +510,14 @@ func TestAccPBIX_rebind_dataset_with_update(t *testing.T) {
 }
 
 func TestAccPBIX_rebind_dataset_with_update_to_reportonly_pbix(t *testing.T) {
+	defer testAccUseCassette(t)()
 	datasetPbixLocation := TempFileName("dataset_", ".pbix")
 	datasetPbixLocationTfFriendly := strings.ReplaceAll(datasetPbixLocation, "\\", "\\\\")
 
 	reportPbixLocation := TempFileName("report_", ".pbix")
 	reportPbixLocationTfFriendly := strings.ReplaceAll(reportPbixLocation, "\\", "\\\\")
 
-	workspaceSuffix := acctest.RandString(6)
+	workspaceSuffix := testAccRandString(6)
 	var dataset1_datasetID string
 	var dataset2_datasetID string
 
@@ -635,10 +644,11 @@ func TestAccPBIX_rebind_dataset_with_update_to_reportonly_pbix(t *testing.T) {
 }
 
 func TestAccPBIX_parameters(t *testing.T) {
+	defer testAccUseCassette(t)()
 	var updatedTime time.Time
 	var datasetID string
 	var groupID string
-	workspaceSuffix := acctest.RandString(6)
+	workspaceSuffix := testAccRandString(6)
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -739,10 +749,11 @@ func TestAccPBIX_parameters(t *testing.T) {
 }
 
 func TestAccPBIX_datasources(t *testing.T) {
+	defer testAccUseCassette(t)()
 	var updatedTime time.Time
 	var datasetID string
 	var groupID string
-	workspaceSuffix := acctest.RandString(6)
+	workspaceSuffix := testAccRandString(6)
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -1175,3 +1186,140 @@ func testCheckURLDatasource(pbixResourceName string, expectedValue string) resou
 		return fmt.Errorf("Expecting datasource with field url value %s to exist. Only the urls %v were found in the datasources", expectedValue, urlValues)
 	}
 }
+
+func TestReadPBIXDatasources_marksDatasourcesWithoutMatchAsUnknown(t *testing.T) {
+	stringPtr := func(s string) *string { return &s }
+	client := &powerbiapifake.Client{
+		GetDatasourcesInGroupFunc: func(ctx context.Context, groupID string, datasetID string) (*powerbiapi.GetDatasourcesInGroupResponse, error) {
+			return &powerbiapi.GetDatasourcesInGroupResponse{
+				Value: []powerbiapi.GetDatasourcesInGroupResponseItem{
+					{ConnectionDetails: powerbiapi.GetDatasourcesInGroupResponseItemConnectionDetails{
+						URL: stringPtr("https://matched.example.com"), Server: stringPtr(""), Database: stringPtr(""),
+					}},
+				},
+			}, nil
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourcePBIX().Schema, map[string]interface{}{
+		"workspace_id": "workspace",
+		"datasource": []interface{}{
+			map[string]interface{}{"url": "https://matched.example.com"},
+			map[string]interface{}{"url": "https://changed.example.com"},
+		},
+	})
+	d.Set("dataset_id", "dataset")
+
+	if err := readPBIXDatasources(context.Background(), d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	var urls []string
+	for _, datasource := range d.Get("datasource").(*schema.Set).List() {
+		urls = append(urls, datasource.(map[string]interface{})["url"].(string))
+	}
+	sort.Strings(urls)
+	if strings.Join(urls, ",") != "???,https://matched.example.com" {
+		t.Fatalf("expected only the unmatched datasource to be unknown but got %v", urls)
+	}
+
+	calls := client.CallsTo("GetDatasourcesInGroup")
+	if len(calls) != 1 || calls[0].Args[0] != "workspace" || calls[0].Args[1] != "dataset" {
+		t.Fatalf("expected datasources of the dataset to be read but got %+v", client.Calls())
+	}
+}
+
+func TestCreatePBIX_timeoutOnlyAppliesToWaitingForImport(t *testing.T) {
+	timeout := 50 * time.Millisecond
+	client := &powerbiapifake.Client{
+		PostImportInGroupFunc: func(ctx context.Context, groupID string, datasetDisplayName string, nameConflict string, skipReport bool, requestData io.Reader) (*powerbiapi.PostImportInGroupResponse, error) {
+			// simulate an upload that takes longer than the timeout
+			time.Sleep(3 * timeout)
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return &powerbiapi.PostImportInGroupResponse{ID: "import"}, nil
+		},
+		WaitForImportInGroupToSucceedFunc: func(ctx context.Context, groupID string, importID string) (*powerbiapi.GetImportInGroupResponse, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				return nil, fmt.Errorf("expected waiting for the import to have a deadline")
+			}
+			return &powerbiapi.GetImportInGroupResponse{
+				ID:       importID,
+				Name:     "pbix",
+				Datasets: []powerbiapi.GetImportInGroupResponseDataset{{ID: "dataset"}},
+			}, nil
+		},
+	}
+
+	pbix := ResourcePBIX()
+	pbix.Timeouts = &schema.ResourceTimeout{Create: schema.DefaultTimeout(timeout)}
+	d := pbix.Data(nil)
+	d.MarkNewResource()
+	d.Set("workspace_id", "workspace")
+	d.Set("name", "pbix")
+	d.Set("source", "./resource_pbix_test_sample1.pbix")
+
+	if err := createPBIX(d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Id() != "import" || d.Get("dataset_id") != "dataset" {
+		t.Fatalf("expected the import to be created but got id %q and dataset_id %q", d.Id(), d.Get("dataset_id"))
+	}
+}
+
+func TestDeletePBIX_ignoresAlreadyDeletedReportAndDataset(t *testing.T) {
+	notFound := powerbiapi.HTTPUnsuccessfulError{
+		Response: &http.Response{Status: "404 Not Found", StatusCode: http.StatusNotFound},
+		Kind:     powerbiapi.ErrorKindNotFound,
+	}
+	client := &powerbiapifake.Client{
+		// deleting the dataset first also deletes the reports bound to it
+		DeleteReportInGroupFunc: func(ctx context.Context, groupID string, reportID string) error {
+			return notFound
+		},
+		DeleteDatasetInGroupFunc: func(ctx context.Context, groupID string, datasetID string) error {
+			return notFound
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourcePBIX().Schema, map[string]interface{}{
+		"workspace_id": "workspace",
+	})
+	d.Set("report_id", "report")
+	d.Set("dataset_id", "dataset")
+
+	if err := deletePBIX(d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(client.CallsTo("DeleteReportInGroup")) != 1 || len(client.CallsTo("DeleteDatasetInGroup")) != 1 {
+		t.Fatalf("expected the report and dataset to be deleted but got %+v", client.Calls())
+	}
+}
+
+func TestDeletePBIX_returnsOtherErrors(t *testing.T) {
+	client := &powerbiapifake.Client{
+		DeleteReportInGroupFunc: func(ctx context.Context, groupID string, reportID string) error {
+			return powerbiapi.HTTPUnsuccessfulError{
+				Response: &http.Response{Status: "403 Forbidden", StatusCode: http.StatusForbidden},
+				Kind:     powerbiapi.ErrorKindForbidden,
+			}
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourcePBIX().Schema, map[string]interface{}{
+		"workspace_id": "workspace",
+	})
+	d.Set("report_id", "report")
+	d.Set("dataset_id", "dataset")
+
+	if err := deletePBIX(d, client); err == nil {
+		t.Fatal("expected the report delete error to be returned")
+	}
+
+	if len(client.CallsTo("DeleteDatasetInGroup")) != 0 {
+		t.Fatalf("expected the dataset not to be deleted but got %+v", client.Calls())
+	}
+}
@@ -18,10 +18,11 @@ func ResourceRefreshSchedule() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"workspace_id": {
-				Type:        schema.TypeString,
-				Description: "Workspace ID in which the dataset was deployed.",
-				Required:    true,
-				ForceNew:    true,
+				Type:             schema.TypeString,
+				Description:      "Workspace ID in which the dataset was deployed. Set to `me`, or omit, when the dataset was deployed to My workspace.",
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressMyWorkspaceDiff,
 			},
 			"dataset_id": {
 				Type:        schema.TypeString,
@@ -71,6 +72,7 @@ func ResourceRefreshSchedule() *schema.Resource {
 					return warns, errs
 				},
 			},
+			"profile_id": profileIDSchema(),
 		},
 	}
 }
@@ -86,16 +88,6 @@ func getDatasetID(d *schema.ResourceData, meta interface{}) (string, error) {
 	return datasetID, nil
 }
 
-func getGroupID(d *schema.ResourceData, meta interface{}) (string, error) {
-	groupID := d.Get("workspace_id").(string)
-
-	if groupID == "" {
-		return "", fmt.Errorf("Unable to determine workspace ID. Ensure workspace_id is set")
-	}
-
-	return groupID, nil
-}
-
 func validateConfig(d *schema.ResourceData, meta interface{}) error {
 	// schema validate functions do not yet support lists and maps
 	// creating own makeshift validation to check days and times
@@ -134,12 +126,15 @@ func validateConfigTimes(d *schema.ResourceData, meta interface{}) error {
 }
 
 func createRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := operationContext(d, meta, schema.TimeoutCreate)
+	defer cancel()
+
 	err := validateConfig(d, meta)
 	if err != nil {
 		return err
 	}
 
-	client := meta.(*powerbiapi.Client)
+	client := meta.(powerbiapi.API)
 
 	enabled := nilIfFalse(d.Get("enabled").(bool))
 	datasetID, err := getDatasetID(d, meta)
@@ -147,12 +142,9 @@ func createRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	groupID, err := getGroupID(d, meta)
-	if err != nil {
-		return err
-	}
+	groupID := d.Get("workspace_id").(string)
 
-	err = client.UpdateRefreshScheduleInGroup(groupID, datasetID, powerbiapi.UpdateRefreshScheduleInGroupRequest{
+	err = client.UpdateRefreshScheduleInGroupWithContext(ctx, groupID, datasetID, powerbiapi.UpdateRefreshScheduleInGroupRequest{
 		Value: powerbiapi.UpdateRefreshScheduleInGroupRequestValue{
 			Enabled:         convertBoolToPointer(true), // API doesnt allow updating if disabled
 			Days:            convertStringSliceToPointer(convertToStringSlice(d.Get("days").([]interface{}))),
@@ -167,7 +159,7 @@ func createRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
 
 	// Set the disabled flag to be the correct value
 	if enabled == nil {
-		err := client.UpdateRefreshScheduleInGroup(groupID, datasetID, powerbiapi.UpdateRefreshScheduleInGroupRequest{
+		err := client.UpdateRefreshScheduleInGroupWithContext(ctx, groupID, datasetID, powerbiapi.UpdateRefreshScheduleInGroupRequest{
 			Value: powerbiapi.UpdateRefreshScheduleInGroupRequestValue{
 				Enabled: convertBoolToPointer(false),
 			},
@@ -181,19 +173,19 @@ func createRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
 }
 
 func readRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutRead)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
 
 	datasetID, err := getDatasetID(d, meta)
 	if err != nil {
 		return err
 	}
-	groupID, err := getGroupID(d, meta)
-	if err != nil {
-		return err
-	}
+	groupID := d.Get("workspace_id").(string)
 
-	refreshSchedule, err := client.GetRefreshScheduleInGroup(groupID, datasetID)
-	if isHTTP404Error(err) {
+	refreshSchedule, err := client.GetRefreshScheduleInGroupWithContext(ctx, groupID, datasetID)
+	if powerbiapi.IsGoneError(err) {
 		d.SetId("")
 		return nil
 	}
@@ -214,12 +206,15 @@ func readRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
 }
 
 func updateRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := operationContext(d, meta, schema.TimeoutUpdate)
+	defer cancel()
+
 	err := validateConfig(d, meta)
 	if err != nil {
 		return err
 	}
 
-	client := meta.(*powerbiapi.Client)
+	client := meta.(powerbiapi.API)
 
 	requestVal := powerbiapi.UpdateRefreshScheduleInGroupRequestValue{}
 	updateRequired := false
@@ -255,13 +250,10 @@ func updateRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
 	if err != nil {
 		return err
 	}
-	groupID, err := getGroupID(d, meta)
-	if err != nil {
-		return err
-	}
+	groupID := d.Get("workspace_id").(string)
 
 	if updateRequired {
-		err := client.UpdateRefreshScheduleInGroup(groupID, datasetID, powerbiapi.UpdateRefreshScheduleInGroupRequest{
+		err := client.UpdateRefreshScheduleInGroupWithContext(ctx, groupID, datasetID, powerbiapi.UpdateRefreshScheduleInGroupRequest{
 			Value: requestVal,
 		})
 		if err != nil {
@@ -271,7 +263,7 @@ func updateRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
 
 	// disabling has to be in a seperate step as api does not allow updates and disable in same request
 	if disableRequired {
-		err := client.UpdateRefreshScheduleInGroup(groupID, datasetID, powerbiapi.UpdateRefreshScheduleInGroupRequest{
+		err := client.UpdateRefreshScheduleInGroupWithContext(ctx, groupID, datasetID, powerbiapi.UpdateRefreshScheduleInGroupRequest{
 			Value: powerbiapi.UpdateRefreshScheduleInGroupRequestValue{Enabled: convertBoolToPointer(false)},
 		})
 		if err != nil {
@@ -283,19 +275,19 @@ func updateRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
 }
 
 func deleteRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutDelete)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
 
 	// You dont delete refresh schedules, so we will disable it
 	datasetID, err := getDatasetID(d, meta)
 	if err != nil {
 		return err
 	}
-	groupID, err := getGroupID(d, meta)
-	if err != nil {
-		return err
-	}
+	groupID := d.Get("workspace_id").(string)
 
-	return client.UpdateRefreshScheduleInGroup(groupID, datasetID, powerbiapi.UpdateRefreshScheduleInGroupRequest{
+	return client.UpdateRefreshScheduleInGroupWithContext(ctx, groupID, datasetID, powerbiapi.UpdateRefreshScheduleInGroupRequest{
 		Value: powerbiapi.UpdateRefreshScheduleInGroupRequestValue{
 			Enabled: convertBoolToPointer(false),
 		},
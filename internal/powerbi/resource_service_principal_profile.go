@@ -0,0 +1,97 @@
+package powerbi
+
+import (
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// ResourceServicePrincipalProfile represents a Power BI service principal profile
+func ResourceServicePrincipalProfile() *schema.Resource {
+	return &schema.Resource{
+		Create: createServicePrincipalProfile,
+		Read:   readServicePrincipalProfile,
+		Update: updateServicePrincipalProfile,
+		Delete: deleteServicePrincipalProfile,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"display_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Display name of the profile.",
+			},
+		},
+	}
+}
+
+func createServicePrincipalProfile(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := operationContext(d, meta, schema.TimeoutCreate)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
+
+	resp, err := client.CreateProfileWithContext(ctx, powerbiapi.CreateProfileRequest{
+		DisplayName: d.Get("display_name").(string),
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(resp.ID)
+
+	return readServicePrincipalProfile(d, meta)
+}
+
+func readServicePrincipalProfile(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := operationContext(d, meta, schema.TimeoutRead)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
+
+	profile, err := client.GetProfileWithContext(ctx, d.Id())
+	if powerbiapi.IsGoneError(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.SetId(profile.ID)
+	d.Set("display_name", profile.DisplayName)
+
+	return nil
+}
+
+func updateServicePrincipalProfile(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := operationContext(d, meta, schema.TimeoutUpdate)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
+
+	if d.HasChange("display_name") {
+		err := client.UpdateProfileWithContext(ctx, d.Id(), powerbiapi.UpdateProfileRequest{
+			DisplayName: d.Get("display_name").(string),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return readServicePrincipalProfile(d, meta)
+}
+
+func deleteServicePrincipalProfile(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := operationContext(d, meta, schema.TimeoutDelete)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
+
+	err := client.DeleteProfileWithContext(ctx, d.Id())
+	if powerbiapi.IsGoneError(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,163 @@
+package powerbi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapifake"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccServicePrincipalProfile_basic(t *testing.T) {
+	suffix := acctest.RandString(6)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if os.Getenv("POWERBI_USERNAME") != "" {
+				t.Skip("Service principal profile acceptance tests require service principal authentication")
+			}
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckServicePrincipalProfileDestroy,
+		Steps: []resource.TestStep{
+			// first step creates a profile and a workspace managed as the profile
+			{
+				Config: fmt.Sprintf(`
+				resource "powerbi_service_principal_profile" "test" {
+					display_name = "Acceptance Test Profile %s"
+				}
+
+				resource "powerbi_workspace" "test" {
+					name       = "Acceptance Test Profile Workspace %s"
+					profile_id = powerbi_service_principal_profile.test.id
+				}
+				`, suffix, suffix),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("powerbi_service_principal_profile.test", "id"),
+					resource.TestCheckResourceAttr("powerbi_service_principal_profile.test", "display_name", fmt.Sprintf("Acceptance Test Profile %s", suffix)),
+					resource.TestCheckResourceAttrPair("powerbi_workspace.test", "profile_id", "powerbi_service_principal_profile.test", "id"),
+					testCheckWorkspaceOnlyVisibleToProfile("powerbi_workspace.test"),
+				),
+			},
+			// second step renames the profile without replacing it or its workspace
+			{
+				Config: fmt.Sprintf(`
+				resource "powerbi_service_principal_profile" "test" {
+					display_name = "Acceptance Test Profile %s - Updated"
+				}
+
+				resource "powerbi_workspace" "test" {
+					name       = "Acceptance Test Profile Workspace %s"
+					profile_id = powerbi_service_principal_profile.test.id
+				}
+				`, suffix, suffix),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_service_principal_profile.test", "display_name", fmt.Sprintf("Acceptance Test Profile %s - Updated", suffix)),
+					testCheckWorkspaceOnlyVisibleToProfile("powerbi_workspace.test"),
+				),
+			},
+			// final step checks importing the profile
+			{
+				ResourceName:      "powerbi_service_principal_profile.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testCheckWorkspaceOnlyVisibleToProfile(rn string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		client := testAccProvider.Meta().(*powerbiapi.Client)
+		profileCtx := powerbiapi.WithProfileID(context.Background(), rs.Primary.Attributes["profile_id"])
+		workspace, err := client.GetGroupWithContext(profileCtx, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if workspace == nil {
+			return fmt.Errorf("workspace with ID '%s' does not exist for the profile", rs.Primary.ID)
+		}
+
+		workspace, err = client.GetGroupWithContext(powerbiapi.WithProfileID(context.Background(), ""), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if workspace != nil {
+			return fmt.Errorf("workspace with ID '%s' was created as the service principal rather than the profile", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckServicePrincipalProfileDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*powerbiapi.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "powerbi_service_principal_profile" {
+			continue
+		}
+
+		_, err := client.GetProfile(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("profile '%s' still exists", rs.Primary.ID)
+		}
+		if !powerbiapi.IsNotFoundError(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestDeleteServicePrincipalProfile_ignoresAlreadyDeletedProfile(t *testing.T) {
+	client := &powerbiapifake.Client{
+		DeleteProfileFunc: func(ctx context.Context, profileID string) error {
+			return powerbiapi.HTTPUnsuccessfulError{
+				Response: &http.Response{Status: "404 Not Found", StatusCode: http.StatusNotFound},
+				Kind:     powerbiapi.ErrorKindNotFound,
+			}
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceServicePrincipalProfile().Schema, map[string]interface{}{})
+	d.SetId("profile")
+
+	if err := deleteServicePrincipalProfile(d, client); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.CallsTo("DeleteProfile")) != 1 {
+		t.Fatalf("expected the profile to be deleted but got %+v", client.Calls())
+	}
+}
+
+func TestDeleteServicePrincipalProfile_returnsOtherErrors(t *testing.T) {
+	client := &powerbiapifake.Client{
+		DeleteProfileFunc: func(ctx context.Context, profileID string) error {
+			return powerbiapi.HTTPUnsuccessfulError{
+				Response: &http.Response{Status: "403 Forbidden", StatusCode: http.StatusForbidden},
+				Kind:     powerbiapi.ErrorKindForbidden,
+			}
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceServicePrincipalProfile().Schema, map[string]interface{}{})
+	d.SetId("profile")
+
+	if err := deleteServicePrincipalProfile(d, client); err == nil {
+		t.Fatal("expected an error when the profile could not be deleted")
+	}
+}
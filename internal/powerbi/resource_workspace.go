@@ -1,6 +1,7 @@
 package powerbi
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
@@ -22,24 +23,37 @@ func ResourceWorkspace() *schema.Resource {
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 				Description: "Name of the workspace.",
 			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of the workspace. Descriptions can only be read and updated through the admin APIs, so the provider `use_admin_apis` must be enabled to set it.",
+			},
 			"capacity_id": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "Capacity ID to be assigned to workspace.",
 			},
+			"profile_id": profileIDSchema(),
 		},
 	}
 }
 
 func createWorkspace(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutCreate)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
 
 	capacityID := d.Get("capacity_id").(string)
 
-	resp, err := client.CreateGroup(powerbiapi.CreateGroupRequest{
+	// check before the workspace is created so a description that cannot be set does not leave a tainted workspace
+	if err := checkDescriptionCanBeSet(d, client); err != nil {
+		return err
+	}
+
+	resp, err := client.CreateGroupWithContext(ctx, powerbiapi.CreateGroupRequest{
 		Name: d.Get("name").(string),
 	})
 	if err != nil {
@@ -48,8 +62,16 @@ func createWorkspace(d *schema.ResourceData, meta interface{}) error {
 
 	d.SetId(resp.ID)
 
+	// workspaces cannot be given a description when they are created
+	if description := d.Get("description").(string); description != "" {
+		err := updateWorkspaceDetails(ctx, d, meta)
+		if err != nil {
+			return err
+		}
+	}
+
 	if capacityID != "" {
-		err := assignToCapacity(d, meta)
+		err := assignToCapacity(ctx, d, meta)
 		if err != nil {
 			return err
 		}
@@ -59,9 +81,16 @@ func createWorkspace(d *schema.ResourceData, meta interface{}) error {
 }
 
 func readWorkspace(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutRead)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
 
-	workspace, err := client.GetGroup(d.Id())
+	workspace, err := client.GetGroupWithContext(ctx, d.Id())
+	if powerbiapi.IsGoneError(err) {
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
@@ -76,6 +105,15 @@ func readWorkspace(d *schema.ResourceData, meta interface{}) error {
 		} else {
 			d.Set("capacity_id", "")
 		}
+
+		// descriptions are only returned by the admin APIs
+		if client.UseAdminAPIs() {
+			adminWorkspace, err := client.GetGroupAsAdminWithContext(ctx, d.Id())
+			if err != nil {
+				return err
+			}
+			d.Set("description", adminWorkspace.Description)
+		}
 	}
 
 	return nil
@@ -83,12 +121,22 @@ func readWorkspace(d *schema.ResourceData, meta interface{}) error {
 
 func updateWorkspace(d *schema.ResourceData, meta interface{}) error {
 
+	ctx, cancel := operationContext(d, meta, schema.TimeoutUpdate)
+	defer cancel()
+
+	if d.HasChanges("name", "description") {
+		err := updateWorkspaceDetails(ctx, d, meta)
+		if err != nil {
+			return err
+		}
+	}
+
 	if d.HasChange("capacity_id") {
 		if capacityID := d.Get("capacity_id").(string); capacityID == "" {
 			d.Set("capacity_id", "00000000-0000-0000-0000-000000000000")
 		}
 
-		err := assignToCapacity(d, meta)
+		err := assignToCapacity(ctx, d, meta)
 		if err != nil {
 			return err
 		}
@@ -98,19 +146,53 @@ func updateWorkspace(d *schema.ResourceData, meta interface{}) error {
 }
 
 func deleteWorkspace(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutDelete)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
+
+	return client.DeleteGroupWithContext(ctx, d.Id())
+}
+
+// updateWorkspaceDetails updates the name and description of the workspace, through the admin APIs when the
+// provider is configured to use them. Only the admin APIs can update the description
+func updateWorkspaceDetails(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(powerbiapi.API)
 
-	return client.DeleteGroup(d.Id())
+	name := d.Get("name").(string)
+	description := d.Get("description").(string)
+
+	if client.UseAdminAPIs() {
+		return client.UpdateGroupAsAdminWithContext(ctx, d.Id(), powerbiapi.UpdateGroupAsAdminRequest{
+			Name:        name,
+			Description: &description,
+		})
+	}
+	if err := checkDescriptionCanBeSet(d, client); err != nil {
+		return err
+	}
+	return client.UpdateGroupWithContext(ctx, d.Id(), powerbiapi.UpdateGroupRequest{
+		Name: name,
+	})
+}
+
+// checkDescriptionCanBeSet returns an error when the description is changed without the admin APIs, which
+// are the only APIs that can update it
+func checkDescriptionCanBeSet(d *schema.ResourceData, client powerbiapi.API) error {
+	if !client.UseAdminAPIs() && d.HasChange("description") {
+		return fmt.Errorf("Unable to set the description of workspace '%s'. Descriptions can only be updated through the admin APIs, enable the provider use_admin_apis to set it", d.Get("name").(string))
+	}
+	return nil
 }
 
-func assignToCapacity(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*powerbiapi.Client)
+func assignToCapacity(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(powerbiapi.API)
 
 	capacityID := d.Get("capacity_id").(string)
 	if capacityID != "00000000-0000-0000-0000-000000000000" {
 		var capacityObjFound bool
 
-		capacityList, err := client.GetCapacities()
+		capacityList, err := client.GetCapacitiesWithContext(ctx)
 		if err != nil {
 			return err
 		}
@@ -127,7 +209,7 @@ func assignToCapacity(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
-	err := client.GroupAssignToCapacity(d.Id(), powerbiapi.GroupAssignToCapacityRequest{
+	err := client.GroupAssignToCapacityWithContext(ctx, d.Id(), powerbiapi.GroupAssignToCapacityRequest{
 		CapacityID: capacityID,
 	})
 	if err != nil {
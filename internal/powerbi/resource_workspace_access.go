@@ -61,6 +61,7 @@ func ResourceGroupUsers() *schema.Resource {
 				Required:     true,
 				ValidateFunc: validation.StringInSlice([]string{"User", "App", "Group"}, false),
 			},
+			"profile_id": profileIDSchema(),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Default: schema.DefaultTimeout(5 * time.Minute),
@@ -70,6 +71,9 @@ func ResourceGroupUsers() *schema.Resource {
 
 func addGroupUser(d *schema.ResourceData, meta interface{}) error {
 
+	ctx, cancel := operationContext(d, meta, schema.TimeoutCreate)
+	defer cancel()
+
 	groupID := d.Get("workspace_id").(string)
 
 	Identifier := d.Get("identifier").(string)
@@ -77,8 +81,8 @@ func addGroupUser(d *schema.ResourceData, meta interface{}) error {
 		Identifier = d.Get("email_address").(string)
 	}
 
-	client := meta.(*powerbiapi.Client)
-	err := client.AddGroupUser(groupID, powerbiapi.AddGroupUserRequest{
+	client := meta.(powerbiapi.API)
+	err := client.AddGroupUserWithContext(ctx, groupID, powerbiapi.AddGroupUserRequest{
 		GroupUserAccessRight: d.Get("group_user_access_right").(string),
 		DisplayName:          d.Get("display_name").(string),
 		PrincipalType:        d.Get("principal_type").(string),
@@ -89,7 +93,7 @@ func addGroupUser(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	workspaceObj, err := client.GetGroup(groupID)
+	workspaceObj, err := client.GetGroupWithContext(ctx, groupID)
 	if err != nil {
 		return err
 	}
@@ -105,17 +109,24 @@ func addGroupUser(d *schema.ResourceData, meta interface{}) error {
 
 func readGroupUser(d *schema.ResourceData, meta interface{}) error {
 
-	client := meta.(*powerbiapi.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutRead)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
 
 	groupID := d.Get("workspace_id").(string)
 	var workspace string
 
 	if groupID == "" {
 		workspace = strings.SplitN(d.Id(), "/", 2)[0]
-		workspaceObj, err := client.GetGroupByName(workspace)
+		workspaceObj, err := client.GetGroupByNameWithContext(ctx, workspace)
 		if err != nil {
 			return err
 		}
+		if workspaceObj == nil {
+			d.SetId("")
+			return nil
+		}
 		groupID = workspaceObj.ID
 	}
 
@@ -130,14 +141,20 @@ func readGroupUser(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Could not find user identifier")
 	}
 
-	groupUsers, err := client.GetGroupUsers(groupID)
+	groupUsers, err := client.GetGroupUsersWithContext(ctx, groupID)
+	if powerbiapi.IsGoneError(err) {
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
+	found := false
 	if len(groupUsers.Value) >= 1 {
 		for _, apiOUTuserObj := range groupUsers.Value {
-			if apiOUTuserObj.Identifier == Identifier {
+			if strings.EqualFold(apiOUTuserObj.Identifier, Identifier) {
+				found = true
 				d.Set("identifier", apiOUTuserObj.Identifier)
 				d.Set("group_user_access_right", apiOUTuserObj.GroupUserAccessRight)
 				d.Set("display_name", apiOUTuserObj.DisplayName)
@@ -148,27 +165,38 @@ func readGroupUser(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	// the user has been removed from the workspace out of band
+	if !found {
+		d.SetId("")
+	}
+
 	return nil
 }
 
 func updateGroupUser(d *schema.ResourceData, meta interface{}) error {
 
-	client := meta.(*powerbiapi.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutUpdate)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
 
 	groupID := d.Get("workspace_id").(string)
 	var workspace string
 
 	if groupID == "" {
 		workspace = strings.SplitN(d.Id(), "/", 2)[0]
-		workspaceObj, err := client.GetGroupByName(workspace)
+		workspaceObj, err := client.GetGroupByNameWithContext(ctx, workspace)
 		if err != nil {
 			return err
 		}
+		if workspaceObj == nil {
+			return fmt.Errorf("Unable to update access to workspace '%s'. The workspace was not found", workspace)
+		}
 		groupID = workspaceObj.ID
 	}
 
 	if d.HasChange("group_user_access_right") {
-		err := client.UpdateGroupUser(groupID, powerbiapi.UpdateGroupUserRequest{
+		err := client.UpdateGroupUserWithContext(ctx, groupID, powerbiapi.UpdateGroupUserRequest{
 			GroupUserAccessRight: d.Get("group_user_access_right").(string),
 			//DisplayName:          d.Get("display_name").(string),
 			//PrincipalType:        d.Get("principal_type").(string),
@@ -187,17 +215,24 @@ func updateGroupUser(d *schema.ResourceData, meta interface{}) error {
 
 func deleteGroupUser(d *schema.ResourceData, meta interface{}) error {
 
-	client := meta.(*powerbiapi.Client)
+	ctx, cancel := operationContext(d, meta, schema.TimeoutDelete)
+	defer cancel()
+
+	client := meta.(powerbiapi.API)
 
 	groupID := d.Get("workspace_id").(string)
 	var workspace string
 
 	if groupID == "" {
 		workspace = strings.SplitN(d.Id(), "/", 2)[0]
-		workspaceObj, err := client.GetGroupByName(workspace)
+		workspaceObj, err := client.GetGroupByNameWithContext(ctx, workspace)
 		if err != nil {
 			return err
 		}
+		if workspaceObj == nil {
+			d.SetId("")
+			return nil
+		}
 		groupID = workspaceObj.ID
 	}
 
@@ -212,5 +247,5 @@ func deleteGroupUser(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Could not find user identifier")
 	}
 
-	return client.DeleteUserInGroup(groupID, Identifier)
+	return client.DeleteUserInGroupWithContext(ctx, groupID, Identifier)
 }
@@ -1,19 +1,22 @@
 package powerbi
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"regexp"
 	"testing"
 
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapifake"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
 func TestAccWorkspaceAccess_basic(t *testing.T) {
-	workspaceSuffix := acctest.RandString(6)
+	defer testAccUseCassette(t)()
+	workspaceSuffix := testAccRandString(6)
 	secondaryUsername := os.Getenv("POWERBI_SECONDARY_USERNAME")
 
 	resource.Test(t, resource.TestCase{
@@ -58,6 +61,7 @@ func TestAccWorkspaceAccess_basic(t *testing.T) {
 }
 
 func TestAccWorkspaceAccess_validation(t *testing.T) {
+	defer testAccUseCassette(t)()
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
@@ -111,9 +115,10 @@ func TestAccWorkspaceAccess_validation(t *testing.T) {
 }
 
 func TestAccWorkspaceAccess_skew(t *testing.T) {
+	defer testAccUseCassette(t)()
 	var workspaceUserID string
 	var groupID string
-	workspaceSuffix := acctest.RandString(6)
+	workspaceSuffix := testAccRandString(6)
 	secondaryUsername := os.Getenv("POWERBI_SECONDARY_USERNAME")
 
 	config := fmt.Sprintf(`
@@ -208,3 +213,24 @@ func testCheckGroupUserExistsInWorkspace(workspaceResourceName string, expectedI
 		return nil
 	}
 }
+
+func TestUpdateGroupUser_returnsErrorWhenWorkspaceIsMissing(t *testing.T) {
+	client := &powerbiapifake.Client{
+		GetGroupByNameFunc: func(ctx context.Context, groupName string) (*powerbiapi.GetGroupResponse, error) {
+			return nil, nil
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceGroupUsers().Schema, map[string]interface{}{
+		"group_user_access_right": "Member",
+	})
+	d.SetId("Missing Workspace/user@example.com")
+
+	err := updateGroupUser(d, client)
+	if err == nil || !regexp.MustCompile("workspace was not found").MatchString(err.Error()) {
+		t.Fatalf("expected an error when the workspace is missing but got %v", err)
+	}
+	if d.Id() == "" {
+		t.Fatal("expected the resource to stay in state when the update fails")
+	}
+}
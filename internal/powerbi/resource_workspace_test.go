@@ -1,18 +1,23 @@
 package powerbi
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapifake"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
 func TestAccWorkspace_basic(t *testing.T) {
+	var workspaceID string
 	workspaceSuffix := acctest.RandString(6)
 
 	resource.Test(t, resource.TestCase{
@@ -31,9 +36,10 @@ func TestAccWorkspace_basic(t *testing.T) {
 					testCheckWorkspaceExistsWithName("powerbi_workspace.test", fmt.Sprintf("Acceptance Test Workspace %s", workspaceSuffix)),
 					resource.TestCheckResourceAttrSet("powerbi_workspace.test", "id"),
 					resource.TestCheckResourceAttr("powerbi_workspace.test", "name", fmt.Sprintf("Acceptance Test Workspace %s", workspaceSuffix)),
+					set("powerbi_workspace.test", "id", &workspaceID),
 				),
 			},
-			// second step updates it with a new title
+			// second step renames it without replacing it
 			{
 				Config: fmt.Sprintf(`
 				resource "powerbi_workspace" "test" {
@@ -42,7 +48,7 @@ func TestAccWorkspace_basic(t *testing.T) {
 				`, workspaceSuffix),
 				Check: resource.ComposeTestCheckFunc(
 					testCheckWorkspaceExistsWithName("powerbi_workspace.test", fmt.Sprintf("Acceptance Test Workspace %s - Updated", workspaceSuffix)),
-					resource.TestCheckResourceAttrSet("powerbi_workspace.test", "id"),
+					resource.TestCheckResourceAttrPtr("powerbi_workspace.test", "id", &workspaceID),
 					resource.TestCheckResourceAttr("powerbi_workspace.test", "name", fmt.Sprintf("Acceptance Test Workspace %s - Updated", workspaceSuffix)),
 				),
 			},
@@ -56,6 +62,49 @@ func TestAccWorkspace_basic(t *testing.T) {
 	})
 }
 
+func TestAccWorkspace_description(t *testing.T) {
+	workspaceSuffix := acctest.RandString(6)
+	config := func(useAdminAPIs bool, description string) string {
+		return fmt.Sprintf(`
+		provider "powerbi" {
+			use_admin_apis = %t
+		}
+
+		resource "powerbi_workspace" "test" {
+			name        = "Acceptance Test Workspace %s"
+			description = "%s"
+		}
+		`, useAdminAPIs, workspaceSuffix, description)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			// first step creates the workspace with a description through the admin APIs
+			{
+				Config: config(true, "Initial description"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_workspace.test", "description", "Initial description"),
+				),
+			},
+			// second step updates the description through the admin APIs
+			{
+				Config: config(true, "Updated description"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_workspace.test", "description", "Updated description"),
+				),
+			},
+			// third step checks the description cannot be changed without the admin APIs
+			{
+				Config:      config(false, "Rejected description"),
+				ExpectError: regexp.MustCompile("enable the provider use_admin_apis"),
+			},
+		},
+	})
+}
+
 func TestAccWorkspace_capacity(t *testing.T) {
 	workspaceSuffix := acctest.RandString(6)
 	isPremiumCapacity := os.Getenv("POWERBI_IS_PREMIUM")
@@ -230,3 +279,97 @@ func testAccCheckPowerbiWorkspaceDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUpdateWorkspaceDetails_usesAdminAPIsWhenEnabled(t *testing.T) {
+	client := &powerbiapifake.Client{
+		AdminAPIs: true,
+		UpdateGroupAsAdminFunc: func(ctx context.Context, groupID string, request powerbiapi.UpdateGroupAsAdminRequest) error {
+			return nil
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceWorkspace().Schema, map[string]interface{}{
+		"name":        "Renamed",
+		"description": "Described",
+	})
+	d.SetId("workspace")
+
+	if err := updateWorkspaceDetails(context.Background(), d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := client.CallsTo("UpdateGroupAsAdmin")
+	if len(calls) != 1 || calls[0].Args[0] != "workspace" {
+		t.Fatalf("expected a single admin update of the workspace but got %+v", client.Calls())
+	}
+	request := calls[0].Args[1].(powerbiapi.UpdateGroupAsAdminRequest)
+	if request.Name != "Renamed" || *request.Description != "Described" {
+		t.Fatalf("expected the name and description to be updated but got %+v", request)
+	}
+}
+
+func TestUpdateWorkspaceDetails_onlyRenamesWithoutAdminAPIs(t *testing.T) {
+	client := &powerbiapifake.Client{
+		UpdateGroupFunc: func(ctx context.Context, groupID string, request powerbiapi.UpdateGroupRequest) error {
+			return nil
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceWorkspace().Schema, map[string]interface{}{
+		"name": "Renamed",
+	})
+	d.SetId("workspace")
+
+	if err := updateWorkspaceDetails(context.Background(), d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := client.Calls()
+	if len(calls) != 1 || calls[0].Method != "UpdateGroup" || calls[0].Args[1] != (powerbiapi.UpdateGroupRequest{Name: "Renamed"}) {
+		t.Fatalf("expected the workspace to be renamed through the group API but got %+v", calls)
+	}
+
+	described := schema.TestResourceDataRaw(t, ResourceWorkspace().Schema, map[string]interface{}{
+		"name":        "Renamed",
+		"description": "Described",
+	})
+	described.SetId("workspace")
+
+	if err := updateWorkspaceDetails(context.Background(), described, client); err == nil {
+		t.Fatal("expected an error when the description is set without the admin APIs")
+	}
+	if len(client.Calls()) != 1 {
+		t.Fatalf("expected the workspace not to be updated but got %+v", client.Calls())
+	}
+}
+
+func TestReadWorkspace_readsDescriptionThroughAdminAPIs(t *testing.T) {
+	for _, useAdminAPIs := range []bool{false, true} {
+		client := &powerbiapifake.Client{
+			AdminAPIs: useAdminAPIs,
+			GetGroupFunc: func(ctx context.Context, groupID string) (*powerbiapi.GetGroupResponse, error) {
+				return &powerbiapi.GetGroupResponse{ID: groupID, Name: "Workspace"}, nil
+			},
+			GetGroupAsAdminFunc: func(ctx context.Context, groupID string) (*powerbiapi.GetGroupAsAdminResponse, error) {
+				return &powerbiapi.GetGroupAsAdminResponse{ID: groupID, Name: "Workspace", Description: "Described"}, nil
+			},
+		}
+
+		d := schema.TestResourceDataRaw(t, ResourceWorkspace().Schema, map[string]interface{}{
+			"name": "Workspace",
+		})
+		d.SetId("workspace")
+
+		if err := readWorkspace(d, client); err != nil {
+			t.Fatal(err)
+		}
+
+		expected, expectedAdminCalls := "", 0
+		if useAdminAPIs {
+			expected, expectedAdminCalls = "Described", 1
+		}
+		if d.Get("description") != expected || len(client.CallsTo("GetGroupAsAdmin")) != expectedAdminCalls {
+			t.Fatalf("expected description %q when admin APIs are %t but got %q after %+v", expected, useAdminAPIs, d.Get("description"), client.Calls())
+		}
+	}
+}
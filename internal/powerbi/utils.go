@@ -1,10 +1,11 @@
 package powerbi
 
 import (
-	"net/url"
+	"context"
 	"reflect"
 
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
 
 func convertStringToPointer(s string) *string {
@@ -27,6 +28,14 @@ func convertToStringSlice(interfaceSlice []interface{}) []string {
 	return stringSlice
 }
 
+func convertToIntSlice(interfaceSlice []interface{}) []int {
+	intSlice := make([]int, len(interfaceSlice))
+	for i := range interfaceSlice {
+		intSlice[i] = interfaceSlice[i].(int)
+	}
+	return intSlice
+}
+
 func nilIfFalse(b bool) *bool {
 	if !b {
 		return nil
@@ -41,35 +50,6 @@ func emptyStringToNil(input string) *string {
 	return &input
 }
 
-func isHTTP404Error(err error) bool {
-	if httpErr, isHTTPErr := toHTTPUnsuccessfulError(err); isHTTPErr && httpErr.Response.StatusCode == 404 {
-		return true
-	}
-	return false
-}
-
-func isHTTP401Error(err error) bool {
-	if httpErr, isHTTPErr := toHTTPUnsuccessfulError(err); isHTTPErr && httpErr.Response.StatusCode == 401 {
-		return true
-	}
-	return false
-}
-
-func toHTTPUnsuccessfulError(err error) (*powerbiapi.HTTPUnsuccessfulError, bool) {
-	if err == nil {
-		return nil, false
-	}
-
-	if urlErr, isURLErr := err.(*url.Error); isURLErr {
-		err = urlErr.Unwrap()
-	}
-
-	if httpErr, isHTTPErr := err.(powerbiapi.HTTPUnsuccessfulError); isHTTPErr {
-		return &httpErr, true
-	}
-	return nil, false
-}
-
 type wrappedError struct {
 	Err          error
 	ErrorMessage func(err error) string
@@ -131,3 +111,35 @@ func intersect(as []interface{}, bs []interface{}, isEqualFn func(a interface{},
 	}
 	return result
 }
+
+// operationContext creates a context for a resource operation that is cancelled when the
+// operation timeout elapses or Terraform is interrupted. Requests are made as the resource's
+// profile_id when it is set
+func operationContext(d *schema.ResourceData, meta interface{}, timeoutKey string) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(resourceContext(d, meta), d.Timeout(timeoutKey))
+}
+
+// resourceContext creates a context for a resource operation that is only cancelled when Terraform
+// is interrupted. Requests are made as the resource's profile_id when it is set
+func resourceContext(d *schema.ResourceData, meta interface{}) context.Context {
+	ctx := meta.(powerbiapi.API).BaseContext()
+	if profileID, ok := d.GetOk("profile_id"); ok {
+		ctx = powerbiapi.WithProfileID(ctx, profileID.(string))
+	}
+	return ctx
+}
+
+// profileIDSchema is the schema of the profile_id argument that overrides the provider profile_id for a resource
+func profileIDSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "The ID of the service principal profile the resource is managed as. Defaults to the provider `profile_id`.",
+	}
+}
+
+// suppressMyWorkspaceDiff ignores changes between the ways My workspace can be referred to, `me` or no workspace ID
+func suppressMyWorkspaceDiff(k, old, new string, d *schema.ResourceData) bool {
+	return powerbiapi.IsMyWorkspace(old) && powerbiapi.IsMyWorkspace(new)
+}
@@ -1,18 +1,53 @@
 package powerbiapi
 
 import (
-	"fmt"
+	"context"
 	"net/url"
 )
 
 // UpdateGroupAsAdminRequest represents the request to the UpdateGroupAsAdmin API
 type UpdateGroupAsAdminRequest struct {
-	Name string `json:"name"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
+// GetGroupAsAdminResponse represents the response from the GetGroupAsAdmin API
+type GetGroupAsAdminResponse struct {
+	ID                    string
+	Name                  string
+	Description           string
+	Type                  string
+	State                 string
+	IsOnDedicatedCapacity bool
+	CapacityID            string
+}
+
+// GetGroupAsAdmin returns a workspace, including the details that are only available to Power BI administrators such as its description
+func (client *Client) GetGroupAsAdmin(groupID string) (*GetGroupAsAdminResponse, error) {
+	return client.GetGroupAsAdminWithContext(context.Background(), groupID)
+}
+
+// GetGroupAsAdminWithContext is the same as GetGroupAsAdmin with the addition of a context to cancel the request
+func (client *Client) GetGroupAsAdminWithContext(ctx context.Context, groupID string) (*GetGroupAsAdminResponse, error) {
+
+	var respObj GetGroupAsAdminResponse
+	url := client.url("/admin/groups/%s", url.PathEscape(groupID))
+	err := client.doJSON(ctx, "GET", url, nil, &respObj)
+
+	return &respObj, err
 }
 
 // UpdateGroupAsAdmin updates a workspace
 func (client *Client) UpdateGroupAsAdmin(groupID string, request UpdateGroupAsAdminRequest) error {
+	return client.UpdateGroupAsAdminWithContext(context.Background(), groupID, request)
+}
+
+// UpdateGroupAsAdminWithContext is the same as UpdateGroupAsAdmin with the addition of a context to cancel the request
+func (client *Client) UpdateGroupAsAdminWithContext(ctx context.Context, groupID string, request UpdateGroupAsAdminRequest) error {
+
+	url := client.url("/admin/groups/%s", url.PathEscape(groupID))
+	err := client.doJSON(ctx, "PATCH", url, request, nil)
+	client.cache.invalidate(groupsCacheKey)
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/admin/groups/%s", url.PathEscape(groupID))
-	return client.doJSON("PATCH", url, request, nil)
+	return err
 }
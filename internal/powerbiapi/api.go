@@ -0,0 +1,122 @@
+package powerbiapi
+
+import (
+	"context"
+	"io"
+)
+
+// GroupsAPI manages workspaces and the users that can access them
+type GroupsAPI interface {
+	CreateGroupWithContext(ctx context.Context, request CreateGroupRequest) (*CreateGroupResponse, error)
+	GetGroupsWithContext(ctx context.Context, filter string, top int, skip int) (*GetGroupsResponse, error)
+	GetGroupWithContext(ctx context.Context, groupID string) (*GetGroupResponse, error)
+	GetGroupByNameWithContext(ctx context.Context, groupName string) (*GetGroupResponse, error)
+	DeleteGroupWithContext(ctx context.Context, groupID string) error
+	GetGroupUsersWithContext(ctx context.Context, groupID string) (*GetGroupUsersResponse, error)
+	AddGroupUserWithContext(ctx context.Context, groupID string, request AddGroupUserRequest) error
+	UpdateGroupUserWithContext(ctx context.Context, groupID string, request UpdateGroupUserRequest) error
+	DeleteUserInGroupWithContext(ctx context.Context, groupID string, userInfo string) error
+	RefreshUserPermissionsWithContext(ctx context.Context) error
+	UpdateGroupWithContext(ctx context.Context, groupID string, request UpdateGroupRequest) error
+	UpdateGroupAsAdminWithContext(ctx context.Context, groupID string, request UpdateGroupAsAdminRequest) error
+	GetGroupAsAdminWithContext(ctx context.Context, groupID string) (*GetGroupAsAdminResponse, error)
+}
+
+// ImportsAPI imports PBIX files into workspaces
+type ImportsAPI interface {
+	PostImportInGroupWithContext(ctx context.Context, groupID string, datasetDisplayName string, nameConflict string, skipReport bool, requestData io.Reader) (*PostImportInGroupResponse, error)
+	CreateTemporaryUploadLocationInGroupWithContext(ctx context.Context, groupID string) (*CreateTemporaryUploadLocationResponse, error)
+	UploadToTemporaryUploadLocationWithContext(ctx context.Context, uploadURL string, content io.Reader) error
+	PostImportFromFileURLInGroupWithContext(ctx context.Context, groupID string, datasetDisplayName string, nameConflict string, skipReport bool, fileURL string) (*PostImportInGroupResponse, error)
+	PostLargeImportInGroupWithContext(ctx context.Context, groupID string, datasetDisplayName string, nameConflict string, skipReport bool, requestData io.Reader) (*PostImportInGroupResponse, error)
+	WaitForImportInGroupToSucceedWithContext(ctx context.Context, groupID string, importID string) (*GetImportInGroupResponse, error)
+	GetImportInGroupWithContext(ctx context.Context, groupID string, importID string) (*GetImportInGroupResponse, error)
+	GetImportsInGroupWithContext(ctx context.Context, groupID string) (*GetImportsInGroupResponse, error)
+}
+
+// DatasetsAPI manages datasets, their parameters, datasources and refresh schedules, and push datasets
+type DatasetsAPI interface {
+	GetDatasetInGroupWithContext(ctx context.Context, groupID string, datasetID string) (*GetDatasetInGroupResponse, error)
+	GetDatasetsInGroupWithContext(ctx context.Context, groupID string) (*GetDatasetsInGroupResponse, error)
+	DeleteDatasetInGroupWithContext(ctx context.Context, groupID string, datasetID string) error
+	GetParametersInGroupWithContext(ctx context.Context, groupID string, datasetID string) (*GetParametersInGroupResponse, error)
+	UpdateParametersInGroupWithContext(ctx context.Context, groupID string, datasetID string, request UpdateParametersInGroupRequest) error
+	GetDatasourcesInGroupWithContext(ctx context.Context, groupID string, datasetID string) (*GetDatasourcesInGroupResponse, error)
+	UpdateDatasourcesInGroupWithContext(ctx context.Context, groupID string, datasetID string, request UpdateDatasourcesInGroupRequest) error
+	GetRefreshScheduleInGroupWithContext(ctx context.Context, groupID string, datasetID string) (*GetRefreshScheduleInGroupResponse, error)
+	UpdateRefreshScheduleInGroupWithContext(ctx context.Context, groupID string, datasetID string, request UpdateRefreshScheduleInGroupRequest) error
+	PostDatasetInGroupWithContext(ctx context.Context, groupID string, defaultRetentionPolicy string, request PostDatasetInGroupRequest) (*PostDatasetInGroupResponse, error)
+	GetTablesWithContext(ctx context.Context, datasetID string) (*GetTablesResponse, error)
+	PutTableInGroupWithContext(ctx context.Context, groupID string, datasetID string, tableName string, request PutTableInGroupRequest) error
+	PostRowsInGroupWithContext(ctx context.Context, groupID string, datasetID string, tableName string, request PostRowsInGroupRequest) error
+}
+
+// ReportsAPI manages reports
+type ReportsAPI interface {
+	GetReportsInGroupWithContext(ctx context.Context, groupID string) (*GetReportsInGroupResponse, error)
+	GetReportInGroupWithContext(ctx context.Context, groupID string, reportID string) (*GetReportInGroupResponse, error)
+	DeleteReportInGroupWithContext(ctx context.Context, groupID string, reportID string) error
+	RebindReportInGroupWithContext(ctx context.Context, groupID string, reportID string, request RebindReportInGroupRequest) error
+}
+
+// GatewaysAPI manages gateways and their datasources
+type GatewaysAPI interface {
+	CreateDatasourceWithContext(ctx context.Context, gatewayId string, request CreateDatasourceRequest) error
+	DeleteDatasourceWithContext(ctx context.Context, gatewayId string, datasourceId string) error
+	DeleteDatasourceUserWithContext(ctx context.Context, gatewayId string, datasourceId string, emailAdress string) error
+	AddDatasourceUserWithContext(ctx context.Context, gatewayId string, datasourceId string, request AddDatasouceUserRequest) error
+	GetGatewaysWithContext(ctx context.Context) (*GetGatewaysResponse, error)
+	GetGatewayWithContext(ctx context.Context, gatewayId string) (*GetGatewaysResponseItem, error)
+	GetDatasourcesWithContext(ctx context.Context, gatewayId string) (*GetDatasourcesResponse, error)
+	GetDatasourceWithContext(ctx context.Context, gatewayId string, datasourceId string) (*GetDatasourcesResponseItem, error)
+	GetDatasourceStatusWithContext(ctx context.Context, gatewayId string, datasourceId string) (*GetDatasourceStatusResponse, error)
+	GetDatasourceUsersWithContext(ctx context.Context, gatewayId string, datasourceId string) (*GetDatasourceUsersResponse, error)
+}
+
+// CapacitiesAPI manages capacities and the workspaces assigned to them
+type CapacitiesAPI interface {
+	GroupAssignToCapacityWithContext(ctx context.Context, groupID string, request GroupAssignToCapacityRequest) error
+	GetCapacitiesWithContext(ctx context.Context) (*GetCapacitiesResponse, error)
+}
+
+// ProfilesAPI manages the service principal profiles of the service principal
+type ProfilesAPI interface {
+	CreateProfileWithContext(ctx context.Context, request CreateProfileRequest) (*CreateProfileResponse, error)
+	GetProfilesWithContext(ctx context.Context) (*GetProfilesResponse, error)
+	GetProfileWithContext(ctx context.Context, profileID string) (*GetProfileResponse, error)
+	UpdateProfileWithContext(ctx context.Context, profileID string, request UpdateProfileRequest) error
+	DeleteProfileWithContext(ctx context.Context, profileID string) error
+}
+
+// API is the Power BI REST API used by the provider. Client implements it, tests can use powerbiapifake.Client instead.
+// Only the methods accepting a context are included, iterators are not included as they are specific to Client
+type API interface {
+	GroupsAPI
+	ImportsAPI
+	DatasetsAPI
+	ReportsAPI
+	GatewaysAPI
+	CapacitiesAPI
+	ProfilesAPI
+
+	// BaseContext returns the context requests are derived from, it is cancelled when Terraform requests the provider to stop
+	BaseContext() context.Context
+
+	// UseAdminAPIs determines if items are updated through the admin APIs rather than as a member of the item
+	UseAdminAPIs() bool
+}
+
+var _ API = (*Client)(nil)
+
+// BaseContext returns StopContext, or a background context when StopContext is not set
+func (client *Client) BaseContext() context.Context {
+	if client.StopContext == nil {
+		return context.Background()
+	}
+	return client.StopContext
+}
+
+// UseAdminAPIs returns the UseAdminAPIs option the client was created with
+func (client *Client) UseAdminAPIs() bool {
+	return client.useAdminAPIs
+}
@@ -1,7 +1,7 @@
 package powerbiapi
 
 import (
-	"fmt"
+	"context"
 	"net/url"
 )
 
@@ -31,16 +31,36 @@ type CapacityAdmins string
 
 // GroupAssignToCapacity assigns capcity to a workspace
 func (client *Client) GroupAssignToCapacity(groupID string, request GroupAssignToCapacityRequest) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/AssignToCapacity", url.PathEscape(groupID))
-	err := client.doJSON("POST", url, &request, nil)
+	return client.GroupAssignToCapacityWithContext(context.Background(), groupID, request)
+}
+
+// GroupAssignToCapacityWithContext is the same as GroupAssignToCapacity with the addition of a context to cancel the request
+func (client *Client) GroupAssignToCapacityWithContext(ctx context.Context, groupID string, request GroupAssignToCapacityRequest) error {
+	url := client.url("/groups/%s/AssignToCapacity", url.PathEscape(groupID))
+	err := client.doJSON(ctx, "POST", url, &request, nil)
+	client.cache.invalidate(groupsCacheKey)
 
 	return err
 }
 
 // GetCapacities Returns a list of capacities the user has access to.
 func (client *Client) GetCapacities() (*GetCapacitiesResponse, error) {
-	var respObj GetCapacitiesResponse
-	err := client.doJSON("GET", "https://api.powerbi.com/v1.0/myorg/capacities", nil, &respObj)
+	return client.GetCapacitiesWithContext(context.Background())
+}
+
+// GetCapacitiesWithContext is the same as GetCapacities with the addition of a context to cancel the request
+func (client *Client) GetCapacitiesWithContext(ctx context.Context) (*GetCapacitiesResponse, error) {
+	capacities, err := client.cache.get(ctx, client.profileCacheKey(ctx, capacitiesCacheKey), func() (interface{}, error) {
+		var respObj GetCapacitiesResponse
+		err := client.doJSON(ctx, "GET", client.url("/capacities"), nil, &respObj)
+		return respObj.Value, err
+	})
+	if err != nil {
+		return &GetCapacitiesResponse{}, err
+	}
 
-	return &respObj, err
+	// copy so callers cannot modify the cached response
+	return &GetCapacitiesResponse{
+		Value: append([]GetCapacitiesResponseItem(nil), capacities.([]GetCapacitiesResponseItem)...),
+	}, nil
 }
@@ -2,74 +2,236 @@ package powerbiapi
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
-	"time"
-
-	"github.com/hashicorp/go-cleanhttp"
+	"net/url"
+	"strings"
 )
 
 // Client allows calling the Power BI service
 type Client struct {
 	*http.Client
+	endpoints Endpoints
+
+	// blobClient uploads to temporary upload locations, these are authorized by
+	// the SAS token in the URL so must not be sent the Power BI bearer token
+	blobClient *http.Client
+
+	// defaultProfileID is the service principal profile requests are made as, unless overridden by WithProfileID
+	defaultProfileID string
+
+	// pollOptions determines how often long running operations are checked
+	pollOptions PollOptions
+
+	// cache holds lookups that are repeated for every resource, it is nil when caching is disabled
+	cache *responseCache
+
+	// useAdminAPIs determines if resources update items through the admin APIs
+	useAdminAPIs bool
+
+	// StopContext is cancelled when Terraform requests the provider to stop. Resources derive
+	// their operation contexts from it so in-flight requests are abandoned on interrupt
+	StopContext context.Context
+}
+
+// ClientOptions represents the settings used to construct a Client
+type ClientOptions struct {
+	Endpoints Endpoints
+	// Retry determines how failed requests are retried. DefaultRetryOptions are used when nil
+	Retry *RetryOptions
+	// Throttle limits the rate and concurrency of requests. Requests are unlimited by default
+	Throttle ThrottleOptions
+	// Recorder records or replays every request, including token requests. Requests are sent as is when nil
+	Recorder *Recorder
+	// Transport determines how connections are made, such as through a proxy or trusting additional root certificates
+	Transport TransportOptions
+	// ProfileID is the service principal profile requests are made as. Requests are made as the service principal
+	// itself when empty. WithProfileID overrides the profile for individual requests
+	ProfileID string
+	// Poll determines how often long running operations, such as imports, are checked. DefaultPollOptions are used when unset
+	Poll PollOptions
+	// Cache determines how long workspace and capacity lookups are cached. Lookups are not cached by default
+	Cache CacheOptions
+	// UseAdminAPIs makes resources update items through the admin APIs, so a Power BI administrator can update
+	// items they are not a member of
+	UseAdminAPIs bool
 }
 
 //NewClientWithPasswordAuth creates a Power BI REST API client using password authentication with delegated permissions
-func NewClientWithPasswordAuth(tenant string, clientID string, clientSecret string, username string, password string) (*Client, error) {
-	return newClient(func(httpClient *http.Client) (string, error) {
-		return getAuthTokenWithPassword(httpClient, tenant, clientID, clientSecret, username, password)
+func NewClientWithPasswordAuth(options ClientOptions, tenant string, clientID string, clientSecret string, username string, password string) (*Client, error) {
+	return newClient(options, func(httpClient *http.Client) (*accessToken, error) {
+		return getAuthTokenWithPassword(httpClient, options.Endpoints, tenant, clientID, clientSecret, username, password)
 	})
 }
 
 //NewClientWithClientCredentialAuth creates a Power BI REST API client using client credentials with application permissions
-func NewClientWithClientCredentialAuth(tenant string, clientID string, clientSecret string) (*Client, error) {
+func NewClientWithClientCredentialAuth(options ClientOptions, tenant string, clientID string, clientSecret string) (*Client, error) {
 
-	return newClient(func(httpClient *http.Client) (string, error) {
-		return getAuthTokenWithClientCredentials(httpClient, tenant, clientID, clientSecret)
+	return newClient(options, func(httpClient *http.Client) (*accessToken, error) {
+		return getAuthTokenWithClientCredentials(httpClient, options.Endpoints, tenant, clientID, clientSecret)
 	})
 }
 
-func newClient(getAuthToken func(httpClient *http.Client) (string, error)) (*Client, error) {
+//NewClientWithClientCertificateAuth creates a Power BI REST API client using a client certificate with application permissions.
+//The certificate can either be PFX or PEM encoded and must contain the RSA private key
+func NewClientWithClientCertificateAuth(options ClientOptions, tenant string, clientID string, certificateData []byte, certificatePassword string) (*Client, error) {
+
+	certificate, err := parseClientCertificate(certificateData, certificatePassword)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(options, func(httpClient *http.Client) (*accessToken, error) {
+		return getAuthTokenWithClientCertificate(httpClient, options.Endpoints, tenant, clientID, certificate)
+	})
+}
+
+//NewClientWithFederatedTokenAuth creates a Power BI REST API client using workload identity federation with application permissions.
+//A federated (OIDC) token from the CI system is exchanged for a Power BI token, so no client secret is required
+func NewClientWithFederatedTokenAuth(options ClientOptions, tenant string, clientID string, federatedToken FederatedTokenOptions) (*Client, error) {
+
+	if err := federatedToken.validate(); err != nil {
+		return nil, err
+	}
+
+	return newClient(options, func(httpClient *http.Client) (*accessToken, error) {
+		return getAuthTokenWithFederatedToken(httpClient, options.Endpoints, tenant, clientID, federatedToken)
+	})
+}
+
+//NewClientWithManagedIdentityAuth creates a Power BI REST API client using an Azure managed identity with application permissions
+func NewClientWithManagedIdentityAuth(options ClientOptions, managedIdentity ManagedIdentityOptions) (*Client, error) {
+	return newClient(options, func(httpClient *http.Client) (*accessToken, error) {
+		return getAuthTokenWithManagedIdentity(httpClient, options.Endpoints, managedIdentity)
+	})
+}
+
+//NewClientWithAccessToken creates a Power BI REST API client using an access token that has already been acquired.
+//The token cannot be refreshed, so it must remain valid for the duration of the terraform run
+func NewClientWithAccessToken(options ClientOptions, token string) (*Client, error) {
+	return newClient(options, func(httpClient *http.Client) (*accessToken, error) {
+		return getAuthTokenWithAccessToken(token)
+	})
+}
+
+//NewClientWithTokenCommand creates a Power BI REST API client that gets access tokens by running a local command.
+//The command must print a JSON object containing 'access_token' (or 'accessToken') and optionally its expiry
+func NewClientWithTokenCommand(options ClientOptions, command string) (*Client, error) {
+	return newClient(options, func(httpClient *http.Client) (*accessToken, error) {
+		return getAuthTokenWithTokenCommand(command)
+	})
+}
+
+func newClient(options ClientOptions, getAuthToken func(httpClient *http.Client) (*accessToken, error)) (*Client, error) {
+
+	if options.Endpoints.APIURL == "" {
+		return nil, fmt.Errorf("Power BI API URL must be set")
+	}
+
+	defaultTransport, err := newTransport(options.Transport, true)
+	if err != nil {
+		return nil, err
+	}
+	defaultTokenTransport, err := newTransport(options.Transport, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// token requests use their own transport so they are never sent the bearer token
+	var apiTransport, tokenTransport http.RoundTripper = defaultTransport, defaultTokenTransport
+	if options.Recorder != nil {
+		apiTransport = options.Recorder.roundTripper(apiTransport)
+		tokenTransport = options.Recorder.roundTripper(tokenTransport)
+	}
+
+	retryOptions := DefaultRetryOptions()
+	if options.Retry != nil {
+		retryOptions = *options.Retry
+	}
+
+	// error
+	unauthenticatedTransport := newErrorOnUnsuccessfulRoundTripper(
+		// retry too many requests and intermittent errors
+		newRetryRoundTripper(
+			retryOptions,
+			// limit the rate of every attempt so we throttle ourselves before the service does
+			newThrottleRoundTripper(
+				options.Throttle,
+				// log every attempt
+				newLoggingRoundTripper(
+					"Power BI",
+					// actual call
+					apiTransport,
+				),
+			),
+		),
+	)
 
-	// PowerBI has lots of intermittant TLS handshake issues, these settings
-	// seem to reduce the amount of issues encountered
-	defaultTransport := cleanhttp.DefaultPooledTransport()
-	defaultTransport.TLSHandshakeTimeout = 60 * time.Second
-	defaultTransport.TLSClientConfig = &tls.Config{
-		MinVersion: tls.VersionTLS12,
+	client := &Client{
+		blobClient:       &http.Client{Transport: unauthenticatedTransport},
+		endpoints:        options.Endpoints,
+		defaultProfileID: options.ProfileID,
+		cache:            newResponseCache(options.Cache),
+		pollOptions:      options.Poll,
+		useAdminAPIs:     options.UseAdminAPIs,
 	}
 
 	// auth
-	httpClient := &http.Client{
+	client.Client = &http.Client{
 		Transport: newBearerTokenRoundTripper(
 			getAuthToken,
-			// error
-			newErrorOnUnsuccessfulRoundTripper(
-				// this is crazy we need to retry 500 and 400 errors, but the API intermittently returns them
-				newRetryIntermittentErrorRoundTripper(
-					// retry too many requests
-					newRetryTooManyRequestsRoundTripper(
-						// actual call
-						defaultTransport,
-					),
-				),
+			tokenTransport,
+			// act as a service principal profile
+			newProfileRoundTripper(
+				client,
+				unauthenticatedTransport,
 			),
 		),
 	}
 
-	return &Client{
-		httpClient,
-	}, nil
+	return client, nil
 }
 
-func (client *Client) doJSON(method string, url string, body interface{}, response interface{}) error {
+// newPoller creates a poller for a long running operation using the client's poll options
+func (client *Client) newPoller(operation string) Poller {
+	return Poller{
+		Operation: operation,
+		Options:   client.pollOptions,
+	}
+}
+
+// url builds a URL to the Power BI REST API for the path relative to /v1.0/myorg
+func (client *Client) url(pathFormat string, a ...interface{}) string {
+	return client.endpoints.APIURL + "/v1.0/myorg" + fmt.Sprintf(pathFormat, a...)
+}
+
+// MyWorkspaceID is the workspace ID of the caller's personal workspace, My workspace
+const MyWorkspaceID = "me"
 
-	httpRequest, err := newJSONRequest(method, url, body)
+// groupURL builds the URL of a workspace scoped endpoint. My workspace is addressed as /myorg/...
+// and other workspaces as /myorg/groups/{groupID}/...
+func (client *Client) groupURL(groupID string, pathFormat string, a ...interface{}) string {
+	if IsMyWorkspace(groupID) {
+		return client.url(pathFormat, a...)
+	}
+	return client.url("/groups/%s%s", url.PathEscape(groupID), fmt.Sprintf(pathFormat, a...))
+}
+
+// IsMyWorkspace determines if the workspace ID refers to the caller's personal workspace, My workspace,
+// which is either MyWorkspaceID or an empty ID
+func IsMyWorkspace(groupID string) bool {
+	return groupID == "" || strings.EqualFold(groupID, MyWorkspaceID)
+}
+
+func (client *Client) doJSON(ctx context.Context, method string, url string, body interface{}, response interface{}) error {
+
+	httpRequest, err := newJSONRequest(ctx, method, url, body)
 	if err != nil {
 		return err
 	}
@@ -82,9 +244,9 @@ func (client *Client) doJSON(method string, url string, body interface{}, respon
 	return newJSONResponse(httpResponse, response)
 }
 
-func (client *Client) doMultipartJSON(method string, url string, body io.Reader, response interface{}) error {
+func (client *Client) doMultipartJSON(ctx context.Context, method string, url string, body io.Reader, response interface{}) error {
 
-	httpRequest, err := newMultipartRequest(method, url, body)
+	httpRequest, err := newMultipartRequest(ctx, method, url, body)
 	if err != nil {
 		return err
 	}
@@ -97,11 +259,11 @@ func (client *Client) doMultipartJSON(method string, url string, body io.Reader,
 	return newJSONResponse(httpResponse, response)
 }
 
-func newJSONRequest(method string, url string, body interface{}) (*http.Request, error) {
+func newJSONRequest(ctx context.Context, method string, url string, body interface{}) (*http.Request, error) {
 
 	// if we have no body so can create a simple request
 	if body == nil {
-		return http.NewRequest(method, url, nil)
+		return http.NewRequestWithContext(ctx, method, url, nil)
 	}
 
 	reqData, err := json.Marshal(body)
@@ -109,7 +271,7 @@ func newJSONRequest(method string, url string, body interface{}) (*http.Request,
 		return nil, err
 	}
 
-	httpRequest, err := http.NewRequest(method, url, bytes.NewBuffer(reqData))
+	httpRequest, err := newReplayableRequest(ctx, method, url, reqData)
 	if err != nil {
 		return nil, err
 	}
@@ -118,32 +280,102 @@ func newJSONRequest(method string, url string, body interface{}) (*http.Request,
 	return httpRequest, nil
 }
 
-func newMultipartRequest(method string, url string, reader io.Reader) (*http.Request, error) {
+// newMultipartRequest creates a request that streams the reader as a single part multipart body.
+// When the reader can seek the content length is precomputed and the body can be replayed by retries
+// from the starting offset, so large files are never held in memory
+func newMultipartRequest(ctx context.Context, method string, url string, reader io.Reader) (*http.Request, error) {
 
-	// Create multipart writer
-	var buffer bytes.Buffer
-	writer := multipart.NewWriter(&buffer)
+	// Write the multipart framing up front, the part content is streamed in between
+	var framing bytes.Buffer
+	writer := multipart.NewWriter(&framing)
+	if _, err := writer.CreatePart(textproto.MIMEHeader{}); err != nil {
+		return nil, err
+	}
+	header := append([]byte(nil), framing.Bytes()...)
+	framing.Reset()
+	writer.Close()
+	footer := append([]byte(nil), framing.Bytes()...)
 
-	partWriter, err := writer.CreatePart(textproto.MIMEHeader{})
+	newBody := func(content io.Reader) io.ReadCloser {
+		return ioutil.NopCloser(io.MultiReader(bytes.NewReader(header), content, bytes.NewReader(footer)))
+	}
+
+	seeker, isSeeker := reader.(io.Seeker)
+	if !isSeeker {
+		// without being able to rewind the content the request is streamed once and cannot be retried
+		req, err := http.NewRequestWithContext(ctx, method, url, newBody(reader))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req, nil
+	}
+
+	start, err := seeker.Seek(0, io.SeekCurrent)
 	if err != nil {
 		return nil, err
 	}
-
-	// Copy everything from reader in writer
-	if _, err = io.Copy(partWriter, reader); err != nil {
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := seeker.Seek(start, io.SeekStart); err != nil {
 		return nil, err
 	}
-	writer.Close()
 
-	// Create the request from our buffer
-	req, err := http.NewRequest(method, url, &buffer)
+	req, err := http.NewRequestWithContext(ctx, method, url, newBody(reader))
 	if err != nil {
 		return nil, err
 	}
+	req.ContentLength = int64(len(header)) + (end - start) + int64(len(footer))
+	req.GetBody = func() (io.ReadCloser, error) {
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return newBody(reader), nil
+	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	return req, nil
 }
 
+// newReplayableRequest creates a request whose body can be read again by retries
+func newReplayableRequest(ctx context.Context, method string, url string, data []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+	return req, nil
+}
+
+// isReplayable determines if the request can be sent again
+func isReplayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewindRequest creates a copy of the request with a fresh body so it can be sent again
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	newRequest := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		newRequest.Body = body
+	}
+	return newRequest, nil
+}
+
+func closeResponse(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+}
+
 func newJSONResponse(httpResponse *http.Response, response interface{}) error {
 	if response == nil {
 		return nil
@@ -6,75 +6,159 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
-
-	"github.com/hashicorp/go-cleanhttp"
+	"time"
 )
 
+const clientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// tokenRefreshMargin is how long before a token expires that we will proactively get a new one
+const tokenRefreshMargin = 5 * time.Minute
+
 type tokenResponse struct {
-	AccessToken string `json:"access_token"`
+	AccessToken string        `json:"access_token"`
+	ExpiresIn   secondsNumber `json:"expires_in"`
+	ExpiresOn   secondsNumber `json:"expires_on"`
+}
+
+// secondsNumber is a number of seconds that may be encoded as either a JSON number or string
+type secondsNumber int64
+
+func (n *secondsNumber) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unable to parse '%s' as a number of seconds", string(data))
+	}
+	*n = secondsNumber(value)
+	return nil
+}
+
+type accessToken struct {
+	Value     string
+	ExpiresOn time.Time
+}
+
+func (token *accessToken) expiresWithin(now time.Time, duration time.Duration) bool {
+	// tokens without a known expiry are used until they are rejected
+	if token.ExpiresOn.IsZero() {
+		return false
+	}
+	return now.Add(duration).After(token.ExpiresOn)
+}
+
+func (resp tokenResponse) toAccessToken(now time.Time) *accessToken {
+	token := &accessToken{
+		Value: resp.AccessToken,
+	}
+	if resp.ExpiresIn > 0 {
+		token.ExpiresOn = now.Add(time.Duration(resp.ExpiresIn) * time.Second)
+	} else if resp.ExpiresOn > 0 {
+		token.ExpiresOn = time.Unix(int64(resp.ExpiresOn), 0)
+	}
+	return token
 }
 
 type bearerTokenRoundTripper struct {
 	innerRoundTripper http.RoundTripper
-	getToken          func(*http.Client) (string, error)
+	tokenTransport    http.RoundTripper
+	getToken          func(*http.Client) (*accessToken, error)
 	mux               sync.Mutex
-	token             string
+	token             *accessToken
+	now               func() time.Time
 }
 
-func newBearerTokenRoundTripper(getToken func(*http.Client) (string, error), next http.RoundTripper) http.RoundTripper {
+func newBearerTokenRoundTripper(getToken func(*http.Client) (*accessToken, error), tokenTransport http.RoundTripper, next http.RoundTripper) http.RoundTripper {
 	return &bearerTokenRoundTripper{
 		innerRoundTripper: next,
+		tokenTransport:    tokenTransport,
 		getToken:          getToken,
+		now:               time.Now,
 	}
 }
 
 func (rt *bearerTokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	newRequest := *req
-
-	if rt.token == "" {
-		err := func() error {
-			rt.mux.Lock()
-			defer rt.mux.Unlock()
-
-			if rt.token == "" {
-
-				// create own http client so we dont try to add token to request to get tokens
-				httpClient := cleanhttp.DefaultClient()
-				httpClient.Transport = newErrorOnUnsuccessfulRoundTripper(httpClient.Transport)
-
-				token, err := rt.getToken(httpClient)
-				if err != nil {
-					return err
-				}
-				rt.token = token
-			}
-			return nil
-		}()
+
+	token, err := rt.validToken("")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := rt.roundTripWithToken(req, token)
+
+	// token may have been revoked or expired earlier than we expected, get a new token and try once more.
+	// Power BI also returns 401 for deleted items, the token is fine in that case so is kept
+	if isUnauthorizedError(err) && !IsGoneError(err) && isReplayable(req) {
+		closeResponse(resp)
+
+		token, err = rt.validToken(token)
 		if err != nil {
 			return nil, err
 		}
+
+		var retryRequest *http.Request
+		retryRequest, err = rewindRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = rt.roundTripWithToken(retryRequest, token)
 	}
 
-	newRequest.Header.Set("Authorization", "Bearer "+rt.token)
+	return resp, err
+}
 
-	return rt.innerRoundTripper.RoundTrip(&newRequest)
+func (rt *bearerTokenRoundTripper) roundTripWithToken(req *http.Request, token string) (*http.Response, error) {
+	newRequest := req.Clone(req.Context())
+	newRequest.Header.Set("Authorization", "Bearer "+token)
+	return rt.innerRoundTripper.RoundTrip(newRequest)
+}
+
+// validToken returns a token that is not close to expiring. If rejectedToken
+// matches the current token it is discarded and a new token is retrieved
+func (rt *bearerTokenRoundTripper) validToken(rejectedToken string) (string, error) {
+	rt.mux.Lock()
+	defer rt.mux.Unlock()
+
+	if rt.token != nil && rejectedToken != "" && rt.token.Value == rejectedToken {
+		rt.token = nil
+	}
+
+	if rt.token == nil || rt.token.expiresWithin(rt.now(), tokenRefreshMargin) {
+
+		// create own http client so we dont try to add token to request to get tokens
+		httpClient := &http.Client{
+			Transport: newErrorOnUnsuccessfulRoundTripper(newTokenLoggingRoundTripper("Power BI token", rt.tokenTransport)),
+		}
+
+		token, err := rt.getToken(httpClient)
+		if err != nil {
+			return "", err
+		}
+		rt.token = token
+	}
+
+	return rt.token.Value, nil
+}
+
+func isUnauthorizedError(err error) bool {
+	httpErr, ok := err.(HTTPUnsuccessfulError)
+	return ok && httpErr.Response != nil && httpErr.Response.StatusCode == http.StatusUnauthorized
 }
 
 func getAuthTokenWithPassword(
 	httpClient *http.Client,
+	endpoints Endpoints,
 	tenant string,
 	clientID string,
 	clientSecret string,
 	username string,
 	password string,
-) (string, error) {
+) (*accessToken, error) {
 
-	authURL := fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", url.PathEscape(tenant))
-	resp, err := httpClient.Post(authURL, "application/x-www-form-urlencoded", strings.NewReader(url.Values{
+	resp, err := httpClient.Post(endpoints.tokenURL(tenant), "application/x-www-form-urlencoded", strings.NewReader(url.Values{
 		"grant_type":    {"password"},
-		"scope":         {"https://analysis.windows.net/powerbi/api/.default"},
+		"scope":         {endpoints.scope()},
 		"client_id":     {clientID},
 		"client_secret": {clientSecret},
 		"username":      {username},
@@ -82,54 +166,74 @@ func getAuthTokenWithPassword(
 	}.Encode()))
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if resp.StatusCode != 200 {
-		data, _ := ioutil.ReadAll(resp.Body)
-		return "", fmt.Errorf("status: %d, body: %s", resp.StatusCode, data)
-	}
-
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	var dataObj tokenResponse
-	err = json.Unmarshal(data, &dataObj)
-	return dataObj.AccessToken, err
+	return readTokenResponse(resp)
 }
 
 func getAuthTokenWithClientCredentials(
 	httpClient *http.Client,
+	endpoints Endpoints,
 	tenant string,
 	clientID string,
 	clientSecret string,
-) (string, error) {
+) (*accessToken, error) {
 
-	authURL := fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", url.PathEscape(tenant))
-	resp, err := httpClient.Post(authURL, "application/x-www-form-urlencoded", strings.NewReader(url.Values{
+	resp, err := httpClient.Post(endpoints.tokenURL(tenant), "application/x-www-form-urlencoded", strings.NewReader(url.Values{
 		"grant_type":    {"client_credentials"},
-		"scope":         {"https://analysis.windows.net/powerbi/api/.default"},
+		"scope":         {endpoints.scope()},
 		"client_id":     {clientID},
 		"client_secret": {clientSecret},
 	}.Encode()))
 
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	return readTokenResponse(resp)
+}
+
+func getAuthTokenWithClientAssertion(
+	httpClient *http.Client,
+	endpoints Endpoints,
+	tenant string,
+	clientID string,
+	assertion string,
+) (*accessToken, error) {
+
+	resp, err := httpClient.Post(endpoints.tokenURL(tenant), "application/x-www-form-urlencoded", strings.NewReader(url.Values{
+		"grant_type":            {"client_credentials"},
+		"scope":                 {endpoints.scope()},
+		"client_id":             {clientID},
+		"client_assertion_type": {clientAssertionTypeJWTBearer},
+		"client_assertion":      {assertion},
+	}.Encode()))
+
+	if err != nil {
+		return nil, err
 	}
 
+	return readTokenResponse(resp)
+}
+
+func readTokenResponse(resp *http.Response) (*accessToken, error) {
+	defer resp.Body.Close()
+
 	if resp.StatusCode != 200 {
 		data, _ := ioutil.ReadAll(resp.Body)
-		return "", fmt.Errorf("status: %d, body: %s", resp.StatusCode, data)
+		return nil, fmt.Errorf("status: %d, body: %s", resp.StatusCode, data)
 	}
 
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var dataObj tokenResponse
 	err = json.Unmarshal(data, &dataObj)
-	return dataObj.AccessToken, err
+	if err != nil {
+		return nil, err
+	}
+	return dataObj.toAccessToken(time.Now()), nil
 }
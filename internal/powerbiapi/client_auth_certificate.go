@@ -0,0 +1,163 @@
+package powerbiapi
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/pkcs12"
+)
+
+// clientCertificate represents the certificate and key used to sign client assertions
+type clientCertificate struct {
+	Certificate *x509.Certificate
+	PrivateKey  *rsa.PrivateKey
+}
+
+// parseClientCertificate reads a certificate and RSA private key from either PFX (PKCS#12) or PEM encoded data
+func parseClientCertificate(data []byte, password string) (*clientCertificate, error) {
+
+	var blocks []*pem.Block
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		for rest := data; ; {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			blocks = append(blocks, block)
+		}
+	} else {
+		pfxBlocks, err := pkcs12.ToPEM(data, password)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read PFX certificate: %v", err)
+		}
+		blocks = pfxBlocks
+	}
+
+	var certificates []*x509.Certificate
+	var privateKey *rsa.PrivateKey
+	for _, block := range blocks {
+		switch {
+		case block.Type == "CERTIFICATE":
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse certificate: %v", err)
+			}
+			certificates = append(certificates, certificate)
+
+		case strings.HasSuffix(block.Type, "PRIVATE KEY") && privateKey == nil:
+			keyBytes := block.Bytes
+			if x509.IsEncryptedPEMBlock(block) {
+				decrypted, err := x509.DecryptPEMBlock(block, []byte(password))
+				if err != nil {
+					return nil, fmt.Errorf("unable to decrypt private key: %v", err)
+				}
+				keyBytes = decrypted
+			}
+			key, err := parseRSAPrivateKey(keyBytes)
+			if err != nil {
+				return nil, err
+			}
+			privateKey = key
+		}
+	}
+
+	if privateKey == nil {
+		return nil, fmt.Errorf("certificate does not contain an RSA private key")
+	}
+
+	// certificate files can contain a chain, use the certificate that belongs to the key
+	for _, certificate := range certificates {
+		if publicKey, ok := certificate.PublicKey.(*rsa.PublicKey); ok && publicKey.N.Cmp(privateKey.N) == 0 {
+			return &clientCertificate{
+				Certificate: certificate,
+				PrivateKey:  privateKey,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("certificate does not contain a certificate matching the private key")
+}
+
+func parseRSAPrivateKey(der []byte) (*rsa.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse private key: %v", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key must be an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// newClientAssertion creates a signed JWT that proves possession of the certificate to Azure Active Directory
+func newClientAssertion(certificate *clientCertificate, clientID string, audience string, now time.Time) (string, error) {
+
+	thumbprint := sha1.Sum(certificate.Certificate.Raw)
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"x5t": base64.RawURLEncoding.EncodeToString(thumbprint[:]),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"aud": audience,
+		"iss": clientID,
+		"sub": clientID,
+		"jti": hex.EncodeToString(jti),
+		"nbf": now.Unix(),
+		"exp": now.Add(10 * time.Minute).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hashed := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, certificate.PrivateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func getAuthTokenWithClientCertificate(
+	httpClient *http.Client,
+	endpoints Endpoints,
+	tenant string,
+	clientID string,
+	certificate *clientCertificate,
+) (*accessToken, error) {
+
+	tokenURL := endpoints.tokenURL(tenant)
+	assertion, err := newClientAssertion(certificate, clientID, tokenURL, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return getAuthTokenWithClientAssertion(httpClient, endpoints, tenant, clientID, assertion)
+}
@@ -0,0 +1,116 @@
+package powerbiapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// tokenCommandTimeout is how long a token command can run before it is killed
+var tokenCommandTimeout = 2 * time.Minute
+
+// tokenCommandResponse represents the JSON printed by a token command. Both OAuth style
+// (access_token) and Azure CLI style (accessToken) property names are accepted
+type tokenCommandResponse struct {
+	AccessToken       string        `json:"access_token"`
+	AccessTokenCLI    string        `json:"accessToken"`
+	ExpiresIn         secondsNumber `json:"expires_in"`
+	ExpiresOn         secondsNumber `json:"expires_on"`
+	ExpiresOnDateTime string        `json:"expiresOn"`
+}
+
+func getAuthTokenWithAccessToken(token string) (*accessToken, error) {
+	if token == "" {
+		return nil, fmt.Errorf("access token is empty")
+	}
+
+	// a pre-acquired token cannot be refreshed, so there is no point trying a new token once it expires
+	expiresOn := readJWTExpiry(token)
+	if !expiresOn.IsZero() && time.Now().After(expiresOn) {
+		return nil, fmt.Errorf("access token expired at %s", expiresOn.Format(time.RFC3339))
+	}
+
+	return &accessToken{
+		Value: token,
+	}, nil
+}
+
+func getAuthTokenWithTokenCommand(command string) (*accessToken, error) {
+
+	// a command waiting for input, such as an interactive login, would otherwise block the terraform run forever
+	ctx, cancel := context.WithTimeout(context.Background(), tokenCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("token command timed out after %s: %s", tokenCommandTimeout, strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("token command failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var dataObj tokenCommandResponse
+	if err := json.Unmarshal(stdout.Bytes(), &dataObj); err != nil {
+		return nil, fmt.Errorf("token command did not output a JSON token: %v", err)
+	}
+
+	token := tokenResponse{
+		AccessToken: dataObj.AccessToken,
+		ExpiresIn:   dataObj.ExpiresIn,
+		ExpiresOn:   dataObj.ExpiresOn,
+	}.toAccessToken(time.Now())
+
+	if token.Value == "" {
+		token.Value = dataObj.AccessTokenCLI
+	}
+	if token.Value == "" {
+		return nil, fmt.Errorf("token command output did not contain 'access_token' or 'accessToken'")
+	}
+	if token.ExpiresOn.IsZero() && dataObj.ExpiresOnDateTime != "" {
+		// Azure CLI outputs the expiry in local time
+		if expiresOn, err := time.ParseInLocation("2006-01-02 15:04:05.999999", dataObj.ExpiresOnDateTime, time.Local); err == nil {
+			token.ExpiresOn = expiresOn
+		}
+	}
+	if token.ExpiresOn.IsZero() {
+		token.ExpiresOn = readJWTExpiry(token.Value)
+	}
+
+	return token, nil
+}
+
+// readJWTExpiry reads the exp claim from a JWT without validating it. A zero time is returned if the token is not a JWT
+func readJWTExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
@@ -0,0 +1,96 @@
+package powerbiapi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClientWithAccessToken(t *testing.T) {
+	api := newBearerTestAPI("pre-acquired-token")
+	defer api.Close()
+
+	client, err := NewClientWithAccessToken(ClientOptions{
+		Endpoints: Endpoints{APIURL: api.URL},
+	}, "pre-acquired-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.RefreshUserPermissions(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetAuthTokenWithAccessToken_rejectsExpiredJWT(t *testing.T) {
+	expired := newTestJWT(time.Now().Add(-time.Minute))
+
+	if _, err := getAuthTokenWithAccessToken(expired); err == nil {
+		t.Fatal("expected an error for an expired access token")
+	}
+}
+
+func TestNewClientWithTokenCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("token command test uses a posix shell")
+	}
+
+	api := newBearerTestAPI("command-token")
+	defer api.Close()
+
+	client, err := NewClientWithTokenCommand(ClientOptions{
+		Endpoints: Endpoints{APIURL: api.URL},
+	}, `echo '{"accessToken":"command-token","expiresOn":"2100-01-01 00:00:00.000000"}'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.RefreshUserPermissions(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetAuthTokenWithTokenCommand_parsesExpiry(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("token command test uses a posix shell")
+	}
+
+	token, err := getAuthTokenWithTokenCommand(`echo '{"access_token":"abc","expires_on":4102444800}'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Value != "abc" || !token.ExpiresOn.Equal(time.Unix(4102444800, 0)) {
+		t.Fatalf("unexpected token %+v", token)
+	}
+
+	_, err = getAuthTokenWithTokenCommand("echo 'not logged in' >&2; exit 3")
+	if err == nil || !strings.Contains(err.Error(), "not logged in") {
+		t.Fatalf("expected an error including stderr when the token command fails but got %v", err)
+	}
+}
+
+func TestGetAuthTokenWithTokenCommand_timesOut(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("token command test uses a posix shell")
+	}
+
+	defer func(timeout time.Duration) { tokenCommandTimeout = timeout }(tokenCommandTimeout)
+	tokenCommandTimeout = 100 * time.Millisecond
+
+	_, err := getAuthTokenWithTokenCommand("echo 'waiting for login' >&2; exec sleep 10")
+	if err == nil {
+		t.Fatal("expected an error when the token command does not finish")
+	}
+	if !strings.Contains(err.Error(), "timed out") || !strings.Contains(err.Error(), "waiting for login") {
+		t.Fatalf("expected a timeout error including stderr but got %v", err)
+	}
+}
+
+func newTestJWT(expiresOn time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, expiresOn.Unix())))
+	return header + "." + claims + ".signature"
+}
@@ -0,0 +1,147 @@
+package powerbiapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultFederatedTokenAudience is the audience Azure Active Directory expects on federated tokens
+const DefaultFederatedTokenAudience = "api://AzureADTokenExchange"
+
+// azureDevOpsOIDCAPIVersion is the Azure DevOps REST API version used to request federated tokens
+const azureDevOpsOIDCAPIVersion = "7.1"
+
+// FederatedTokenOptions represents where the federated (OIDC) token used for workload identity federation is obtained from.
+// Only one source needs to be provided, they are checked in the order Token, TokenFilePath then RequestURL
+type FederatedTokenOptions struct {
+	// Token is a federated token that has already been obtained
+	Token string
+	// TokenFilePath is a file containing the federated token, such as AZURE_FEDERATED_TOKEN_FILE
+	TokenFilePath string
+	// RequestURL is a URL that issues federated tokens, such as ACTIONS_ID_TOKEN_REQUEST_URL in GitHub Actions
+	// or SYSTEM_OIDCREQUESTURI in Azure DevOps
+	RequestURL string
+	// RequestToken is the bearer token used to authenticate against RequestURL, such as ACTIONS_ID_TOKEN_REQUEST_TOKEN
+	// or SYSTEM_ACCESSTOKEN
+	RequestToken string
+	// Audience is the audience requested from RequestURL. Defaults to DefaultFederatedTokenAudience.
+	// Azure DevOps does not support requesting an audience so it is not used with AzureDevOpsServiceConnectionID
+	Audience string
+	// AzureDevOpsServiceConnectionID is the ID of the Azure DevOps service connection the federated token is issued for.
+	// When set RequestURL is treated as the Azure DevOps OIDC request URI, otherwise as a GitHub Actions token request URL
+	AzureDevOpsServiceConnectionID string
+}
+
+type federatedTokenResponse struct {
+	Value     string `json:"value"`
+	OIDCToken string `json:"oidcToken"`
+}
+
+func (options FederatedTokenOptions) validate() error {
+	if options.Token == "" && options.TokenFilePath == "" && options.RequestURL == "" {
+		return fmt.Errorf("a federated token, token file or token request URL must be provided for OIDC authentication")
+	}
+	if options.Token == "" && options.TokenFilePath == "" && options.RequestToken == "" {
+		return fmt.Errorf("a request token must be provided when requesting federated tokens from '%s'", options.RequestURL)
+	}
+	return nil
+}
+
+// getFederatedToken gets a fresh federated token. Federated tokens are typically short lived
+// so they are read again every time a new Power BI token is required
+func getFederatedToken(httpClient *http.Client, options FederatedTokenOptions) (string, error) {
+
+	if options.Token != "" {
+		return options.Token, nil
+	}
+
+	if options.TokenFilePath != "" {
+		data, err := ioutil.ReadFile(options.TokenFilePath)
+		if err != nil {
+			return "", fmt.Errorf("unable to read federated token file: %v", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	req, err := newFederatedTokenRequest(options)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+options.RequestToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var dataObj federatedTokenResponse
+	if err := json.Unmarshal(data, &dataObj); err != nil {
+		return "", err
+	}
+	if dataObj.Value != "" {
+		return dataObj.Value, nil
+	}
+	if dataObj.OIDCToken != "" {
+		return dataObj.OIDCToken, nil
+	}
+	return "", fmt.Errorf("federated token response did not contain a token")
+}
+
+// newFederatedTokenRequest creates the request for a federated token. Azure DevOps issues tokens for a
+// service connection from a POST, GitHub Actions issues tokens for an audience from a GET
+func newFederatedTokenRequest(options FederatedTokenOptions) (*http.Request, error) {
+	requestURL, err := url.Parse(options.RequestURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid federated token request URL: %v", err)
+	}
+	query := requestURL.Query()
+
+	if options.AzureDevOpsServiceConnectionID != "" {
+		query.Set("api-version", azureDevOpsOIDCAPIVersion)
+		query.Set("serviceConnectionId", options.AzureDevOpsServiceConnectionID)
+		requestURL.RawQuery = query.Encode()
+
+		req, err := http.NewRequest("POST", requestURL.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	}
+
+	audience := options.Audience
+	if audience == "" {
+		audience = DefaultFederatedTokenAudience
+	}
+	query.Set("audience", audience)
+	requestURL.RawQuery = query.Encode()
+
+	return http.NewRequest("GET", requestURL.String(), nil)
+}
+
+func getAuthTokenWithFederatedToken(
+	httpClient *http.Client,
+	endpoints Endpoints,
+	tenant string,
+	clientID string,
+	federatedToken FederatedTokenOptions,
+) (*accessToken, error) {
+
+	assertion, err := getFederatedToken(httpClient, federatedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return getAuthTokenWithClientAssertion(httpClient, endpoints, tenant, clientID, assertion)
+}
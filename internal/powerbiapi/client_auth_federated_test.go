@@ -0,0 +1,143 @@
+package powerbiapi
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewClientWithFederatedTokenAuth_fromRequestURL(t *testing.T) {
+	oidc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer request-token" {
+			w.WriteHeader(401)
+			return
+		}
+		if r.Method != "GET" {
+			t.Errorf("expected GitHub Actions tokens to be requested with GET but got %s", r.Method)
+		}
+		if r.URL.Query().Get("audience") != DefaultFederatedTokenAudience {
+			t.Errorf("unexpected audience %s", r.URL.Query().Get("audience"))
+		}
+		w.Write([]byte(`{"value":"federated-token"}`))
+	}))
+	defer oidc.Close()
+
+	login, api := newFederatedTestServers(t, "federated-token")
+	defer login.Close()
+	defer api.Close()
+
+	client, err := NewClientWithFederatedTokenAuth(ClientOptions{
+		Endpoints: Endpoints{APIURL: api.URL, LoginURL: login.URL, ResourceURL: "https://analysis.windows.net/powerbi/api"},
+	}, "my-tenant", "my-client", FederatedTokenOptions{
+		RequestURL:   oidc.URL + "/token?api-version=2.0",
+		RequestToken: "request-token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.RefreshUserPermissions(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClientWithFederatedTokenAuth_fromAzureDevOpsRequestURL(t *testing.T) {
+	oidc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer system-access-token" {
+			w.WriteHeader(401)
+			return
+		}
+		if r.Method != "POST" {
+			t.Errorf("expected Azure DevOps tokens to be requested with POST but got %s", r.Method)
+		}
+		query := r.URL.Query()
+		if query.Get("serviceConnectionId") != "my-service-connection" || query.Get("api-version") != azureDevOpsOIDCAPIVersion {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if query.Get("audience") != "" {
+			t.Errorf("expected no audience to be requested but got %s", query.Get("audience"))
+		}
+		w.Write([]byte(`{"oidcToken":"federated-token"}`))
+	}))
+	defer oidc.Close()
+
+	login, api := newFederatedTestServers(t, "federated-token")
+	defer login.Close()
+	defer api.Close()
+
+	client, err := NewClientWithFederatedTokenAuth(ClientOptions{
+		Endpoints: Endpoints{APIURL: api.URL, LoginURL: login.URL, ResourceURL: "https://analysis.windows.net/powerbi/api"},
+	}, "my-tenant", "my-client", FederatedTokenOptions{
+		RequestURL:                     oidc.URL + "/my-org/my-project/_apis/distributedtask/hubs/build/plans/my-plan/jobs/my-job/oidctoken",
+		RequestToken:                   "system-access-token",
+		AzureDevOpsServiceConnectionID: "my-service-connection",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.RefreshUserPermissions(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClientWithFederatedTokenAuth_fromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "powerbi-oidc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	ioutil.WriteFile(tokenFile, []byte("file-token\n"), 0600)
+
+	login, api := newFederatedTestServers(t, "file-token")
+	defer login.Close()
+	defer api.Close()
+
+	client, err := NewClientWithFederatedTokenAuth(ClientOptions{
+		Endpoints: Endpoints{APIURL: api.URL, LoginURL: login.URL, ResourceURL: "https://analysis.windows.net/powerbi/api"},
+	}, "my-tenant", "my-client", FederatedTokenOptions{
+		TokenFilePath: tokenFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.RefreshUserPermissions(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClientWithFederatedTokenAuth_requiresTokenSource(t *testing.T) {
+	_, err := NewClientWithFederatedTokenAuth(ClientOptions{
+		Endpoints: Endpoints{APIURL: "https://api.powerbi.com"},
+	}, "my-tenant", "my-client", FederatedTokenOptions{})
+	if err == nil {
+		t.Fatal("expected an error when no federated token source is configured")
+	}
+}
+
+func newFederatedTestServers(t *testing.T, expectedAssertion string) (*httptest.Server, *httptest.Server) {
+	login := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("client_assertion_type") != clientAssertionTypeJWTBearer || r.Form.Get("client_assertion") != expectedAssertion {
+			t.Errorf("unexpected client assertion %s", r.Form.Get("client_assertion"))
+			w.WriteHeader(400)
+			return
+		}
+		w.Write([]byte(`{"access_token":"powerbi-token","expires_in":3599}`))
+	}))
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer powerbi-token" {
+			w.WriteHeader(401)
+			return
+		}
+		w.WriteHeader(200)
+	}))
+
+	return login, api
+}
@@ -0,0 +1,65 @@
+package powerbiapi
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// DefaultMSIEndpoint is the Azure Instance Metadata Service endpoint that issues managed identity tokens
+const DefaultMSIEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// ManagedIdentityOptions represents how tokens are obtained for an Azure managed identity
+type ManagedIdentityOptions struct {
+	// ClientID selects a user assigned identity. Leave empty to use the system assigned identity
+	ClientID string
+	// Endpoint is the managed identity token endpoint. Defaults to DefaultMSIEndpoint
+	Endpoint string
+	// IdentityHeader is the App Service IDENTITY_HEADER secret. When set the App Service identity protocol is used
+	IdentityHeader string
+}
+
+func getAuthTokenWithManagedIdentity(
+	httpClient *http.Client,
+	endpoints Endpoints,
+	options ManagedIdentityOptions,
+) (*accessToken, error) {
+
+	msiEndpoint := options.Endpoint
+	if msiEndpoint == "" {
+		msiEndpoint = DefaultMSIEndpoint
+	}
+
+	tokenURL, err := url.Parse(msiEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	queryParams := tokenURL.Query()
+	queryParams.Set("resource", endpoints.ResourceURL)
+	if options.ClientID != "" {
+		queryParams.Set("client_id", options.ClientID)
+	}
+	if options.IdentityHeader != "" {
+		queryParams.Set("api-version", "2019-08-01")
+	} else {
+		queryParams.Set("api-version", "2018-02-01")
+	}
+	tokenURL.RawQuery = queryParams.Encode()
+
+	req, err := http.NewRequest("GET", tokenURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if options.IdentityHeader != "" {
+		req.Header.Set("X-IDENTITY-HEADER", options.IdentityHeader)
+	} else {
+		req.Header.Set("Metadata", "true")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return readTokenResponse(resp)
+}
@@ -0,0 +1,71 @@
+package powerbiapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewClientWithManagedIdentityAuth_instanceMetadata(t *testing.T) {
+	msi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			t.Errorf("expected Metadata header to be sent to the instance metadata endpoint")
+		}
+		if r.URL.Query().Get("resource") != "https://analysis.windows.net/powerbi/api" {
+			t.Errorf("unexpected resource %s", r.URL.Query().Get("resource"))
+		}
+		if r.URL.Query().Get("client_id") != "user-assigned" {
+			t.Errorf("unexpected client_id %s", r.URL.Query().Get("client_id"))
+		}
+		w.Write([]byte(`{"access_token":"msi-token","expires_in":"86399","expires_on":"4102444800"}`))
+	}))
+	defer msi.Close()
+
+	api := newBearerTestAPI("msi-token")
+	defer api.Close()
+
+	client, err := NewClientWithManagedIdentityAuth(ClientOptions{
+		Endpoints: Endpoints{APIURL: api.URL, ResourceURL: "https://analysis.windows.net/powerbi/api"},
+	}, ManagedIdentityOptions{
+		ClientID: "user-assigned",
+		Endpoint: msi.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.RefreshUserPermissions(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClientWithManagedIdentityAuth_appService(t *testing.T) {
+	msi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-IDENTITY-HEADER") != "identity-secret" {
+			w.WriteHeader(401)
+			return
+		}
+		if r.URL.Query().Get("api-version") != "2019-08-01" {
+			t.Errorf("unexpected api-version %s", r.URL.Query().Get("api-version"))
+		}
+		w.Write([]byte(`{"access_token":"app-service-token","expires_on":"4102444800"}`))
+	}))
+	defer msi.Close()
+
+	api := newBearerTestAPI("app-service-token")
+	defer api.Close()
+
+	client, err := NewClientWithManagedIdentityAuth(ClientOptions{
+		Endpoints: Endpoints{APIURL: api.URL, ResourceURL: "https://analysis.windows.net/powerbi/api"},
+	}, ManagedIdentityOptions{
+		Endpoint:       msi.URL,
+		IdentityHeader: "identity-secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.RefreshUserPermissions(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,128 @@
+package powerbiapi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBearerTokenRoundTripper_refreshesBeforeExpiry(t *testing.T) {
+	var tokensIssued int32
+	now := time.Now()
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer api.Close()
+
+	rt := newBearerTokenRoundTripper(func(*http.Client) (*accessToken, error) {
+		atomic.AddInt32(&tokensIssued, 1)
+		return &accessToken{Value: "token", ExpiresOn: now.Add(time.Hour)}, nil
+	}, http.DefaultTransport, http.DefaultTransport).(*bearerTokenRoundTripper)
+	rt.now = func() time.Time { return now }
+
+	sendRequest(t, rt, api.URL, "")
+	sendRequest(t, rt, api.URL, "")
+	if tokensIssued != 1 {
+		t.Fatalf("expected token to be reused, but %d tokens were issued", tokensIssued)
+	}
+
+	// move close enough to expiry that the token should be refreshed
+	now = now.Add(time.Hour - tokenRefreshMargin + time.Second)
+	sendRequest(t, rt, api.URL, "")
+	if tokensIssued != 2 {
+		t.Fatalf("expected token to be refreshed before expiry, but %d tokens were issued", tokensIssued)
+	}
+}
+
+func TestBearerTokenRoundTripper_retriesOnceOnUnauthorized(t *testing.T) {
+	var tokensIssued int32
+	var requests int32
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("expected body 'payload' but received '%s'", body)
+		}
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(401)
+			return
+		}
+		w.WriteHeader(200)
+	}))
+	defer api.Close()
+
+	rt := newBearerTokenRoundTripper(func(*http.Client) (*accessToken, error) {
+		issued := atomic.AddInt32(&tokensIssued, 1)
+		return &accessToken{Value: fmt.Sprintf("token-%d", issued)}, nil
+	}, http.DefaultTransport, newErrorOnUnsuccessfulRoundTripper(http.DefaultTransport))
+
+	sendRequest(t, rt, api.URL, "payload")
+	if tokensIssued != 2 || requests != 2 {
+		t.Fatalf("expected 2 tokens and 2 requests, but got %d tokens and %d requests", tokensIssued, requests)
+	}
+}
+
+func TestBearerTokenRoundTripper_keepsTokenWhenItemIsGone(t *testing.T) {
+	var tokensIssued int32
+	var requests int32
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(401)
+		w.Write([]byte(`{"error":{"code":"ItemNotFound","message":"Couldn't find the item"}}`))
+	}))
+	defer api.Close()
+
+	rt := newBearerTokenRoundTripper(func(*http.Client) (*accessToken, error) {
+		issued := atomic.AddInt32(&tokensIssued, 1)
+		return &accessToken{Value: fmt.Sprintf("token-%d", issued)}, nil
+	}, http.DefaultTransport, newErrorOnUnsuccessfulRoundTripper(http.DefaultTransport))
+
+	req, err := http.NewRequest("GET", api.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = rt.RoundTrip(req)
+	if !IsGoneError(err) {
+		t.Fatalf("expected a gone error but got %v", err)
+	}
+	if tokensIssued != 1 || requests != 1 {
+		t.Fatalf("expected 1 token and 1 request, but got %d tokens and %d requests", tokensIssued, requests)
+	}
+}
+
+func TestTokenResponse_parsesExpiry(t *testing.T) {
+	now := time.Now()
+
+	resp := &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(strings.NewReader(`{"access_token":"abc","expires_in":"3599"}`)),
+	}
+	token, err := readTokenResponse(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Value != "abc" || token.ExpiresOn.Before(now.Add(3598*time.Second)) {
+		t.Fatalf("unexpected token %+v", token)
+	}
+}
+
+func sendRequest(t *testing.T, rt http.RoundTripper, url string, body string) {
+	req, err := http.NewRequest("POST", url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
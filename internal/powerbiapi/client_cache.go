@@ -0,0 +1,135 @@
+package powerbiapi
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheOptions represents how the client caches lookups that are repeated for every resource, such as
+// finding a workspace or listing capacities. A TTL of zero disables the cache
+type CacheOptions struct {
+	// TTL is how long a cached response is used before it is requested again. Mutating calls made through
+	// the client invalidate the responses they affect regardless of TTL
+	TTL time.Duration
+}
+
+const (
+	groupsCacheKey     = "groups"
+	capacitiesCacheKey = "capacities"
+)
+
+// responseCache is a read-through cache of responses. Concurrent reads of a key that is not cached wait for
+// a single request rather than each sending their own. A nil cache does not cache
+type responseCache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mux     sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	loaded  chan struct{}
+	value   interface{}
+	err     error
+	expires time.Time
+}
+
+// newResponseCache creates a cache for the options, returning nil when caching is disabled
+func newResponseCache(options CacheOptions) *responseCache {
+	if options.TTL <= 0 {
+		return nil
+	}
+	return &responseCache{
+		ttl:     options.TTL,
+		now:     time.Now,
+		entries: map[string]*cacheEntry{},
+	}
+}
+
+// get returns the cached value for key, calling load when the key is not cached or has expired.
+// Errors are never cached
+func (cache *responseCache) get(ctx context.Context, key string, load func() (interface{}, error)) (interface{}, error) {
+	if cache == nil {
+		return load()
+	}
+
+	for {
+		cache.mux.Lock()
+		entry, ok := cache.entries[key]
+		if ok && isClosed(entry.loaded) && (entry.err != nil || !cache.now().Before(entry.expires)) {
+			delete(cache.entries, key)
+			ok = false
+		}
+		if !ok {
+			entry = &cacheEntry{loaded: make(chan struct{})}
+			cache.entries[key] = entry
+			cache.mux.Unlock()
+			return cache.load(key, entry, load)
+		}
+		cache.mux.Unlock()
+
+		select {
+		case <-entry.loaded:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		// another caller's request may have failed for reasons that do not apply to this caller, such as
+		// their context being cancelled, so failures are requested again
+		if entry.err == nil {
+			return entry.value, nil
+		}
+	}
+}
+
+func (cache *responseCache) load(key string, entry *cacheEntry, load func() (interface{}, error)) (interface{}, error) {
+	value, err := load()
+
+	cache.mux.Lock()
+	entry.value, entry.err = value, err
+	entry.expires = cache.now().Add(cache.ttl)
+	if err != nil && cache.entries[key] == entry {
+		delete(cache.entries, key)
+	}
+	cache.mux.Unlock()
+	close(entry.loaded)
+
+	return value, err
+}
+
+// invalidate removes keys from the cache, including the key cached for every profile. Requests already in
+// flight for the keys are not cached
+func (cache *responseCache) invalidate(keys ...string) {
+	if cache == nil {
+		return
+	}
+
+	cache.mux.Lock()
+	defer cache.mux.Unlock()
+	for cachedKey := range cache.entries {
+		for _, key := range keys {
+			if cachedKey == key || strings.HasPrefix(cachedKey, key+"/") {
+				delete(cache.entries, cachedKey)
+			}
+		}
+	}
+}
+
+// profileCacheKey returns the key for responses requested with the context. Service principal profiles see
+// different workspaces and capacities so responses are cached separately for each profile
+func (client *Client) profileCacheKey(ctx context.Context, key string) string {
+	if profileID := client.profileID(ctx); profileID != "" {
+		return key + "/" + profileID
+	}
+	return key
+}
+
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,131 @@
+package powerbiapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestResponseCache_expiresAfterTTL(t *testing.T) {
+	now := time.Unix(0, 0)
+	cache := newResponseCache(CacheOptions{TTL: time.Minute})
+	cache.now = func() time.Time { return now }
+
+	loads := 0
+	load := func() (interface{}, error) {
+		loads++
+		return loads, nil
+	}
+
+	first, _ := cache.get(context.Background(), "key", load)
+	now = now.Add(59 * time.Second)
+	cached, _ := cache.get(context.Background(), "key", load)
+	now = now.Add(time.Second)
+	expired, _ := cache.get(context.Background(), "key", load)
+
+	if first != 1 || cached != 1 || expired != 2 {
+		t.Fatalf("expected values 1, 1, 2 but got %v, %v, %v", first, cached, expired)
+	}
+}
+
+func TestResponseCache_doesNotCacheErrors(t *testing.T) {
+	cache := newResponseCache(CacheOptions{TTL: time.Minute})
+
+	_, err := cache.get(context.Background(), "key", func() (interface{}, error) {
+		return nil, errors.New("failed")
+	})
+	if err == nil {
+		t.Fatal("expected the load error to be returned")
+	}
+
+	value, err := cache.get(context.Background(), "key", func() (interface{}, error) {
+		return "loaded", nil
+	})
+	if err != nil || value != "loaded" {
+		t.Fatalf("expected the failed load to be retried but got %v, %v", value, err)
+	}
+}
+
+func TestResponseCache_concurrentReadsShareOneLoad(t *testing.T) {
+	cache := newResponseCache(CacheOptions{TTL: time.Minute})
+
+	var mux sync.Mutex
+	loads := 0
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.get(context.Background(), "key", func() (interface{}, error) {
+				mux.Lock()
+				loads++
+				mux.Unlock()
+				<-release
+				return "loaded", nil
+			})
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if loads != 1 {
+		t.Fatalf("expected a single load but got %d", loads)
+	}
+}
+
+func TestGetGroup_cachedListIsInvalidatedByMutatingCalls(t *testing.T) {
+	var mux sync.Mutex
+	var requests []string
+	groups := `{"id":"group-a","name":"A"},{"id":"group-b","name":"B"}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mux.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "GET" {
+			w.Write([]byte(`{"value":[` + groups + `]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(options *ClientOptions) {
+		options.Cache.TTL = time.Hour
+	})
+
+	for _, id := range []string{"group-a", "group-b", "group-a"} {
+		group, err := client.GetGroup(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if group == nil || group.ID != id {
+			t.Fatalf("expected group %s but got %+v", id, group)
+		}
+	}
+
+	if err := client.DeleteGroup("group-b"); err != nil {
+		t.Fatal(err)
+	}
+	groups = `{"id":"group-a","name":"A"}`
+
+	deleted, err := client.GetGroup("group-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != nil {
+		t.Fatalf("expected the deleted group not to be found but got %+v", deleted)
+	}
+
+	expected := "GET /v1.0/myorg/groups,DELETE /v1.0/myorg/groups/group-b,GET /v1.0/myorg/groups"
+	if strings.Join(requests, ",") != expected {
+		t.Fatalf("expected requests %s but got %s", expected, strings.Join(requests, ","))
+	}
+}
@@ -0,0 +1,84 @@
+package powerbiapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Endpoints represents the URLs used to authenticate against and call the Power BI REST API
+type Endpoints struct {
+	// APIURL is the base URL of the Power BI REST API, e.g. https://api.powerbi.com
+	APIURL string
+	// LoginURL is the base URL of the Azure Active Directory authority, e.g. https://login.microsoftonline.com
+	LoginURL string
+	// ResourceURL is the resource tokens are requested for, e.g. https://analysis.windows.net/powerbi/api
+	ResourceURL string
+}
+
+var environmentEndpoints = map[string]Endpoints{
+	"public": {
+		APIURL:      "https://api.powerbi.com",
+		LoginURL:    "https://login.microsoftonline.com",
+		ResourceURL: "https://analysis.windows.net/powerbi/api",
+	},
+	"usgov": {
+		APIURL:      "https://api.powerbigov.us",
+		LoginURL:    "https://login.microsoftonline.com",
+		ResourceURL: "https://analysis.usgovcloudapi.net/powerbi/api",
+	},
+	"usgovhigh": {
+		APIURL:      "https://api.high.powerbigov.us",
+		LoginURL:    "https://login.microsoftonline.us",
+		ResourceURL: "https://high.analysis.usgovcloudapi.net/powerbi/api",
+	},
+	"dod": {
+		APIURL:      "https://api.mil.powerbigov.us",
+		LoginURL:    "https://login.microsoftonline.us",
+		ResourceURL: "https://mil.analysis.usgovcloudapi.net/powerbi/api",
+	},
+	"china": {
+		APIURL:      "https://api.powerbi.cn",
+		LoginURL:    "https://login.chinacloudapi.cn",
+		ResourceURL: "https://analysis.chinacloudapi.cn/powerbi/api",
+	},
+}
+
+// Environments returns the names of the known Power BI cloud environments
+func Environments() []string {
+	return []string{"public", "usgov", "usgovhigh", "dod", "china"}
+}
+
+// GetEndpointsForEnvironment returns the endpoints for a named Power BI cloud environment
+func GetEndpointsForEnvironment(environment string) (Endpoints, error) {
+	endpoints, ok := environmentEndpoints[strings.ToLower(environment)]
+	if !ok {
+		return Endpoints{}, fmt.Errorf("Unknown environment '%s'. Expected one of %s", environment, strings.Join(Environments(), ", "))
+	}
+	return endpoints, nil
+}
+
+// WithOverrides returns a copy of the endpoints with any non empty values replaced
+func (endpoints Endpoints) WithOverrides(apiURL string, loginURL string, resourceURL string) Endpoints {
+	if apiURL != "" {
+		endpoints.APIURL = apiURL
+	}
+	if loginURL != "" {
+		endpoints.LoginURL = loginURL
+	}
+	if resourceURL != "" {
+		endpoints.ResourceURL = resourceURL
+	}
+	endpoints.APIURL = strings.TrimRight(endpoints.APIURL, "/")
+	endpoints.LoginURL = strings.TrimRight(endpoints.LoginURL, "/")
+	endpoints.ResourceURL = strings.TrimRight(endpoints.ResourceURL, "/")
+	return endpoints
+}
+
+func (endpoints Endpoints) tokenURL(tenant string) string {
+	return fmt.Sprintf("%s/%s/oauth2/v2.0/token", endpoints.LoginURL, url.PathEscape(tenant))
+}
+
+func (endpoints Endpoints) scope() string {
+	return endpoints.ResourceURL + "/.default"
+}
@@ -2,17 +2,50 @@ package powerbiapi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 )
 
+// ErrorKind categorises unsuccessful responses so callers can react to them without inspecting status codes
+type ErrorKind string
+
+const (
+	// ErrorKindNotFound indicates the requested item does not exist
+	ErrorKindNotFound ErrorKind = "NotFound"
+	// ErrorKindForbidden indicates the caller is not authenticated or not authorized to access the item
+	ErrorKindForbidden ErrorKind = "Forbidden"
+	// ErrorKindThrottled indicates the caller has sent too many requests
+	ErrorKindThrottled ErrorKind = "Throttled"
+	// ErrorKindConflict indicates the request conflicts with the current state of the item
+	ErrorKindConflict ErrorKind = "Conflict"
+	// ErrorKindValidation indicates the request was invalid and will not succeed if sent again
+	ErrorKindValidation ErrorKind = "Validation"
+	// ErrorKindTransient indicates a temporary service failure that may succeed if sent again
+	ErrorKindTransient ErrorKind = "Transient"
+	// ErrorKindUnknown indicates an unsuccessful response that does not fit any other kind
+	ErrorKindUnknown ErrorKind = "Unknown"
+)
+
+// transientErrorCodes are Power BI error codes that indicate a temporary failure regardless of status code
+var transientErrorCodes = []string{"ServiceUnavailable", "RequestTimeout", "ServerBusy"}
+
+// goneErrorCodes are Power BI error codes that indicate the item has been deleted. Power BI often returns
+// these with a 401 or 403 rather than a 404 when the item was removed out of band
+var goneErrorCodes = []string{"ItemNotFound", "PowerBIEntityNotFound"}
+
 // HTTPUnsuccessfulError represents an error thrown when a non 2xx response is received
 type HTTPUnsuccessfulError struct {
 	Request      *http.Request
 	Response     *http.Response
 	ErrorBody    *ErrorBody
 	ErrorBodyRaw []byte
+	Kind         ErrorKind
+	// RequestID is the Power BI request ID, Microsoft support needs this to investigate failed requests
+	RequestID string
+	// RoutingRequestID is the request ID assigned by the Azure front door
+	RoutingRequestID string
 }
 
 // ErrorResponse represents the response when the Power BI API returns errors
@@ -24,6 +57,14 @@ type ErrorResponse struct {
 type ErrorBody struct {
 	Code    string
 	Message string
+	Details []ErrorDetail
+}
+
+// ErrorDetail represents a nested error detail returned in the body of Power BI API requests
+type ErrorDetail struct {
+	Code    string
+	Message string
+	Target  string
 }
 
 type errorOnUnsuccessfulRoundTripper struct {
@@ -36,9 +77,114 @@ func (err HTTPUnsuccessfulError) Error() string {
 	if len(err.ErrorBodyRaw) > 0 {
 		message += fmt.Sprintf(" with body %s", string(err.ErrorBodyRaw))
 	}
+	if err.RequestID != "" {
+		message += fmt.Sprintf(" (RequestId: %s)", err.RequestID)
+	}
+	if err.RoutingRequestID != "" {
+		message += fmt.Sprintf(" (x-ms-routing-request-id: %s)", err.RoutingRequestID)
+	}
 	return message
 }
 
+// Code returns the Power BI error code, or an empty string if the response did not contain one
+func (err HTTPUnsuccessfulError) Code() string {
+	if err.ErrorBody == nil {
+		return ""
+	}
+	return err.ErrorBody.Code
+}
+
+// AsHTTPUnsuccessfulError finds the HTTPUnsuccessfulError in the error chain, this includes
+// errors wrapped by the http.Client
+func AsHTTPUnsuccessfulError(err error) (*HTTPUnsuccessfulError, bool) {
+	var httpErr HTTPUnsuccessfulError
+	if err == nil || !errors.As(err, &httpErr) {
+		return nil, false
+	}
+	return &httpErr, true
+}
+
+// IsNotFoundError determines if the error was caused by the requested item not existing
+func IsNotFoundError(err error) bool {
+	return isErrorKind(err, ErrorKindNotFound)
+}
+
+// IsForbiddenError determines if the error was caused by the caller not being authenticated or authorized
+func IsForbiddenError(err error) bool {
+	return isErrorKind(err, ErrorKindForbidden)
+}
+
+// IsThrottledError determines if the error was caused by the caller sending too many requests
+func IsThrottledError(err error) bool {
+	return isErrorKind(err, ErrorKindThrottled)
+}
+
+// IsConflictError determines if the error was caused by a conflict with the current state of the item
+func IsConflictError(err error) bool {
+	return isErrorKind(err, ErrorKindConflict)
+}
+
+// IsValidationError determines if the error was caused by an invalid request
+func IsValidationError(err error) bool {
+	return isErrorKind(err, ErrorKindValidation)
+}
+
+// IsTransientError determines if the error was caused by a temporary service failure
+func IsTransientError(err error) bool {
+	return isErrorKind(err, ErrorKindTransient)
+}
+
+// IsGoneError determines if the error indicates the requested item no longer exists. This includes
+// not found errors and unauthorized or forbidden errors that carry a not found error code
+func IsGoneError(err error) bool {
+	httpErr, ok := AsHTTPUnsuccessfulError(err)
+	if !ok {
+		return false
+	}
+	if httpErr.Kind == ErrorKindNotFound {
+		return true
+	}
+	if httpErr.Kind != ErrorKindForbidden {
+		return false
+	}
+	for _, goneErrorCode := range goneErrorCodes {
+		if httpErr.Code() == goneErrorCode {
+			return true
+		}
+	}
+	return false
+}
+
+func isErrorKind(err error, kind ErrorKind) bool {
+	httpErr, ok := AsHTTPUnsuccessfulError(err)
+	return ok && httpErr.Kind == kind
+}
+
+// classifyError determines the kind of error from the status code and Power BI error code
+func classifyError(statusCode int, errorCode string) ErrorKind {
+	for _, transientErrorCode := range transientErrorCodes {
+		if errorCode == transientErrorCode {
+			return ErrorKindTransient
+		}
+	}
+
+	switch {
+	case statusCode == http.StatusNotFound:
+		return ErrorKindNotFound
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrorKindForbidden
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorKindThrottled
+	case statusCode == http.StatusConflict || statusCode == http.StatusPreconditionFailed:
+		return ErrorKindConflict
+	case statusCode == http.StatusRequestTimeout || statusCode >= 500:
+		return ErrorKindTransient
+	case statusCode >= 400:
+		return ErrorKindValidation
+	}
+	return ErrorKindUnknown
+}
+
 func newErrorOnUnsuccessfulRoundTripper(next http.RoundTripper) http.RoundTripper {
 	return &errorOnUnsuccessfulRoundTripper{
 		innerRoundTripper: next,
@@ -62,9 +208,12 @@ func (h *errorOnUnsuccessfulRoundTripper) RoundTrip(req *http.Request) (*http.Re
 	}
 
 	return resp, HTTPUnsuccessfulError{
-		Request:      req,
-		Response:     resp,
-		ErrorBody:    &errorResponse.Error,
-		ErrorBodyRaw: errorResponseRaw,
+		Request:          req,
+		Response:         resp,
+		ErrorBody:        &errorResponse.Error,
+		ErrorBodyRaw:     errorResponseRaw,
+		Kind:             classifyError(resp.StatusCode, errorResponse.Error.Code),
+		RequestID:        resp.Header.Get("RequestId"),
+		RoutingRequestID: resp.Header.Get("x-ms-routing-request-id"),
 	}
 }
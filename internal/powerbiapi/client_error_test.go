@@ -0,0 +1,107 @@
+package powerbiapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrorOnUnsuccessfulRoundTripper_classifiesErrors(t *testing.T) {
+	testCases := []struct {
+		statusCode   int
+		body         string
+		expectedKind ErrorKind
+		isKind       func(error) bool
+	}{
+		{404, `{"error":{"code":"ItemNotFound"}}`, ErrorKindNotFound, IsNotFoundError},
+		{403, ``, ErrorKindForbidden, IsForbiddenError},
+		{401, ``, ErrorKindForbidden, IsForbiddenError},
+		{429, ``, ErrorKindThrottled, IsThrottledError},
+		{409, `{"error":{"code":"DuplicateName"}}`, ErrorKindConflict, IsConflictError},
+		{400, `{"error":{"code":"InvalidRequest"}}`, ErrorKindValidation, IsValidationError},
+		{400, `{"error":{"code":"ServerBusy"}}`, ErrorKindTransient, IsTransientError},
+		{503, ``, ErrorKindTransient, IsTransientError},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.expectedKind), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(testCase.statusCode)
+				w.Write([]byte(testCase.body))
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: newErrorOnUnsuccessfulRoundTripper(http.DefaultTransport)}
+			_, err := client.Get(server.URL)
+
+			httpErr, ok := AsHTTPUnsuccessfulError(err)
+			if !ok {
+				t.Fatalf("expected HTTPUnsuccessfulError but got %v", err)
+			}
+			if httpErr.Kind != testCase.expectedKind || !testCase.isKind(err) {
+				t.Fatalf("expected kind %s but got %s", testCase.expectedKind, httpErr.Kind)
+			}
+		})
+	}
+}
+
+func TestErrorOnUnsuccessfulRoundTripper_readsDetailsAndRequestIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("RequestId", "request-id")
+		w.Header().Set("x-ms-routing-request-id", "WESTEUROPE:20200101T000000Z:routing-id")
+		w.WriteHeader(400)
+		w.Write([]byte(`{"error":{"code":"InvalidRequest","message":"Invalid request","details":[{"message":"Name is required","target":"name"}]}}`))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newErrorOnUnsuccessfulRoundTripper(http.DefaultTransport)}
+	_, err := client.Get(server.URL)
+
+	httpErr, ok := AsHTTPUnsuccessfulError(err)
+	if !ok {
+		t.Fatalf("expected HTTPUnsuccessfulError but got %v", err)
+	}
+	if httpErr.Code() != "InvalidRequest" {
+		t.Fatalf("unexpected code %s", httpErr.Code())
+	}
+	if len(httpErr.ErrorBody.Details) != 1 || httpErr.ErrorBody.Details[0].Target != "name" {
+		t.Fatalf("unexpected details %+v", httpErr.ErrorBody.Details)
+	}
+	if httpErr.RequestID != "request-id" || httpErr.RoutingRequestID != "WESTEUROPE:20200101T000000Z:routing-id" {
+		t.Fatalf("unexpected request ids %s %s", httpErr.RequestID, httpErr.RoutingRequestID)
+	}
+	if !strings.Contains(err.Error(), "RequestId: request-id") {
+		t.Fatalf("expected error message to contain the request id but got %s", err.Error())
+	}
+}
+
+func TestIsGoneError(t *testing.T) {
+	testCases := []struct {
+		statusCode int
+		body       string
+		expected   bool
+	}{
+		{404, ``, true},
+		{401, `{"error":{"code":"ItemNotFound"}}`, true},
+		{403, `{"error":{"code":"PowerBIEntityNotFound"}}`, true},
+		{403, `{"error":{"code":"PowerBINotAuthorizedException"}}`, false},
+		{401, ``, false},
+		{400, `{"error":{"code":"ItemNotFound"}}`, false},
+	}
+
+	for _, testCase := range testCases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(testCase.statusCode)
+			w.Write([]byte(testCase.body))
+		}))
+
+		client := &http.Client{Transport: newErrorOnUnsuccessfulRoundTripper(http.DefaultTransport)}
+		_, err := client.Get(server.URL)
+		server.Close()
+
+		if IsGoneError(err) != testCase.expected {
+			t.Fatalf("expected IsGoneError to be %v for %d %s", testCase.expected, testCase.statusCode, testCase.body)
+		}
+	}
+}
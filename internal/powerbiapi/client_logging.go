@@ -0,0 +1,255 @@
+package powerbiapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const redacted = "***REDACTED***"
+
+// redactedHeaders are headers that carry credentials and are never logged
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "X-Identity-Header", "Cookie", "Set-Cookie"}
+
+// redactedQueryParameters are URL query parameters that carry credentials, such as the signature of a SAS URL
+var redactedQueryParameters = []string{"sig"}
+
+// redactedFieldFragments identify JSON properties and form fields that carry credentials. Any field whose
+// name contains one of these (case insensitive) has its value redacted
+var redactedFieldFragments = []string{"token", "secret", "password", "credentials", "assertion", "username"}
+
+// tokenRedactedFields are fields that only carry credentials in token endpoint responses. Federated token
+// requests return the token in value, which holds the list of items in Power BI responses
+var tokenRedactedFields = []string{"value"}
+
+type loggingRoundTripper struct {
+	innerRoundTripper http.RoundTripper
+	name              string
+	traceEnabled      func() bool
+	// redactedFields are the names of fields that are redacted in addition to redactedFieldFragments
+	redactedFields []string
+}
+
+func newLoggingRoundTripper(name string, next http.RoundTripper) http.RoundTripper {
+	return &loggingRoundTripper{
+		innerRoundTripper: next,
+		name:              name,
+		traceEnabled:      isTraceLoggingEnabled,
+	}
+}
+
+// newTokenLoggingRoundTripper logs requests to token endpoints, which also redacts the fields tokens are returned in
+func newTokenLoggingRoundTripper(name string, next http.RoundTripper) http.RoundTripper {
+	return &loggingRoundTripper{
+		innerRoundTripper: next,
+		name:              name,
+		traceEnabled:      isTraceLoggingEnabled,
+		redactedFields:    tokenRedactedFields,
+	}
+}
+
+// isTraceLoggingEnabled determines if terraform is logging at TRACE, bodies are only read when it is
+func isTraceLoggingEnabled() bool {
+	for _, env := range []string{"TF_LOG_PROVIDER", "TF_LOG"} {
+		if level := os.Getenv(env); level != "" {
+			return strings.EqualFold(level, "TRACE")
+		}
+	}
+	return false
+}
+
+func (rt *loggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestURL := redactURL(req.URL)
+	trace := rt.traceEnabled()
+
+	log.Printf("[DEBUG] %s request: %s %s", rt.name, req.Method, requestURL)
+	if trace {
+		log.Printf("[TRACE] %s request headers: %s %s\n%s", rt.name, req.Method, requestURL, formatHeaders(req.Header))
+		log.Printf("[TRACE] %s request body: %s %s\n%s", rt.name, req.Method, requestURL, describeRequestBody(req, rt.redactedFields))
+	}
+
+	started := time.Now()
+	resp, err := rt.innerRoundTripper.RoundTrip(req)
+	latency := time.Since(started)
+
+	if err != nil {
+		log.Printf("[DEBUG] %s request failed: %s %s (%v): %v", rt.name, req.Method, requestURL, latency, err)
+		return resp, err
+	}
+
+	log.Printf("[DEBUG] %s response: %s %s %s (%v)", rt.name, resp.Status, req.Method, requestURL, latency)
+	if trace {
+		log.Printf("[TRACE] %s response headers: %s %s\n%s", rt.name, req.Method, requestURL, formatHeaders(resp.Header))
+		log.Printf("[TRACE] %s response body: %s %s\n%s", rt.name, req.Method, requestURL, describeResponseBody(resp, rt.redactedFields))
+	}
+
+	return resp, err
+}
+
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	redactedQuery := false
+	for _, parameter := range redactedQueryParameters {
+		if query.Get(parameter) != "" {
+			query.Set(parameter, redacted)
+			redactedQuery = true
+		}
+	}
+	if !redactedQuery {
+		return u.String()
+	}
+
+	redactedURL := *u
+	redactedURL.RawQuery = query.Encode()
+	return redactedURL.String()
+}
+
+func formatHeaders(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		for _, redactedHeader := range redactedHeaders {
+			if strings.EqualFold(name, redactedHeader) {
+				value = redacted
+			}
+		}
+		fmt.Fprintf(&builder, "%s: %s\n", name, value)
+	}
+	return builder.String()
+}
+
+// describeRequestBody reads a copy of the request body, the body is only read if it can be sent again
+func describeRequestBody(req *http.Request, redactedFields []string) string {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "(empty)"
+	}
+	if !isBodyLoggable(req.Header.Get("Content-Type")) {
+		return describeBodySize(req.Header.Get("Content-Type"), req.ContentLength)
+	}
+	if req.GetBody == nil {
+		return "(body cannot be read without consuming it)"
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Sprintf("(unable to read body: %v)", err)
+	}
+	defer body.Close()
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return fmt.Sprintf("(unable to read body: %v)", err)
+	}
+	return redactBody(req.Header.Get("Content-Type"), data, redactedFields...)
+}
+
+// describeResponseBody buffers the response body so it can be logged and still be read by the caller
+func describeResponseBody(resp *http.Response, redactedFields []string) string {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return "(empty)"
+	}
+	if !isBodyLoggable(resp.Header.Get("Content-Type")) {
+		return describeBodySize(resp.Header.Get("Content-Type"), resp.ContentLength)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Sprintf("(unable to read body: %v)", err)
+	}
+	return redactBody(resp.Header.Get("Content-Type"), data, redactedFields...)
+}
+
+// isBodyLoggable determines if the body is text that is useful in a log, PBIX uploads and blob blocks are not
+func isBodyLoggable(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "", mediaType == "application/x-www-form-urlencoded":
+		return true
+	case strings.HasPrefix(mediaType, "multipart/"), mediaType == "application/octet-stream":
+		return false
+	}
+	return strings.Contains(mediaType, "json") || strings.Contains(mediaType, "xml") || strings.HasPrefix(mediaType, "text/")
+}
+
+func describeBodySize(contentType string, contentLength int64) string {
+	if contentLength < 0 {
+		return fmt.Sprintf("(%s body of unknown size)", contentType)
+	}
+	return fmt.Sprintf("(%s body of %d bytes)", contentType, contentLength)
+}
+
+// redactBody redacts the credentials in a body, redactedFields are the names of fields that are redacted in
+// addition to redactedFieldFragments
+func redactBody(contentType string, data []byte, redactedFields ...string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	if mediaType == "application/x-www-form-urlencoded" {
+		if values, err := url.ParseQuery(string(data)); err == nil {
+			for name := range values {
+				if isRedactedField(name, redactedFields) {
+					values.Set(name, redacted)
+				}
+			}
+			return values.Encode()
+		}
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err == nil {
+		if redactedData, err := json.Marshal(redactJSON(value, redactedFields)); err == nil {
+			return string(redactedData)
+		}
+	}
+
+	return string(data)
+}
+
+func redactJSON(value interface{}, redactedFields []string) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for name, fieldValue := range typedValue {
+			if isRedactedField(name, redactedFields) {
+				typedValue[name] = redacted
+			} else {
+				typedValue[name] = redactJSON(fieldValue, redactedFields)
+			}
+		}
+	case []interface{}:
+		for i := range typedValue {
+			typedValue[i] = redactJSON(typedValue[i], redactedFields)
+		}
+	}
+	return value
+}
+
+func isRedactedField(name string, redactedFields []string) bool {
+	for _, redactedField := range redactedFields {
+		if strings.EqualFold(name, redactedField) {
+			return true
+		}
+	}
+	name = strings.ToLower(name)
+	for _, fragment := range redactedFieldFragments {
+		if strings.Contains(name, fragment) {
+			return true
+		}
+	}
+	return false
+}
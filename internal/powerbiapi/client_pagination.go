@@ -0,0 +1,217 @@
+package powerbiapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// defaultPageSize is the number of items requested per page from endpoints paged with $skip and $top.
+// This is the largest page the Power BI REST API returns
+const defaultPageSize = 5000
+
+// pageResponse represents a single page of a list response. Power BI pages lists in three ways, clients
+// request the next page using $skip and $top, the service returns an @odata.nextLink to the next page,
+// or the service returns a continuation token (admin APIs)
+type pageResponse struct {
+	Value             []json.RawMessage `json:"value"`
+	NextLink          string            `json:"@odata.nextLink"`
+	ContinuationURI   string            `json:"continuationUri"`
+	ContinuationToken string            `json:"continuationToken"`
+	LastResultSet     bool              `json:"lastResultSet"`
+}
+
+// PageIterator streams the items of a paged list, requesting each page as it is needed
+//
+//	iterator := client.GetGroupsIteratorWithContext(ctx, "")
+//	for iterator.Next() {
+//		var group GetGroupsResponseItem
+//		if err := iterator.Decode(&group); err != nil {
+//			return err
+//		}
+//	}
+//	if err := iterator.Err(); err != nil {
+//		return err
+//	}
+type PageIterator struct {
+	client *Client
+	ctx    context.Context
+
+	// baseURL is the URL of the first page, it is reused when following continuation tokens
+	baseURL string
+	nextURL string
+
+	// pageSize is the $top sent when paging with $skip. Zero means the service decides the page size
+	pageSize int
+	skip     int
+
+	// limit is the maximum number of items to return. Zero means all items are returned
+	limit    int
+	returned int
+
+	page    []json.RawMessage
+	current json.RawMessage
+	visited map[string]bool
+	err     error
+}
+
+// newPageIterator creates an iterator over the list at rawURL. When pageSize is greater than zero pages are
+// requested using $skip and $top, otherwise only pages linked by the service are followed
+func (client *Client) newPageIterator(ctx context.Context, rawURL string, pageSize int, skip int, limit int) *PageIterator {
+	iterator := &PageIterator{
+		client:   client,
+		ctx:      ctx,
+		baseURL:  rawURL,
+		pageSize: pageSize,
+		skip:     skip,
+		limit:    limit,
+		visited:  map[string]bool{},
+	}
+	iterator.nextURL, iterator.err = iterator.skipURL()
+	return iterator
+}
+
+// Next advances to the next item, requesting the next page when required. It returns false once all items have been
+// returned or a request fails, Err reports the failure
+func (iterator *PageIterator) Next() bool {
+	if iterator.err != nil || (iterator.limit > 0 && iterator.returned >= iterator.limit) {
+		return false
+	}
+
+	for len(iterator.page) == 0 {
+		if iterator.nextURL == "" {
+			return false
+		}
+		if err := iterator.fetch(); err != nil {
+			iterator.err = err
+			return false
+		}
+	}
+
+	iterator.current, iterator.page = iterator.page[0], iterator.page[1:]
+	iterator.returned++
+	return true
+}
+
+// Decode unmarshals the current item into v
+func (iterator *PageIterator) Decode(v interface{}) error {
+	if iterator.current == nil {
+		return fmt.Errorf("Decode called before Next")
+	}
+	return json.Unmarshal(iterator.current, v)
+}
+
+// Err returns the error that stopped the iteration
+func (iterator *PageIterator) Err() error {
+	return iterator.err
+}
+
+// fetch requests the next page and determines where the page after it is
+func (iterator *PageIterator) fetch() error {
+	pageURL := iterator.nextURL
+	if iterator.visited[pageURL] {
+		return fmt.Errorf("Paging did not advance, the service linked to a page that was already read")
+	}
+	iterator.visited[pageURL] = true
+
+	var page pageResponse
+	if err := iterator.client.doJSON(iterator.ctx, "GET", pageURL, nil, &page); err != nil {
+		return err
+	}
+	iterator.page = page.Value
+
+	var err error
+	switch {
+	case page.NextLink != "":
+		iterator.nextURL, err = resolveURL(pageURL, page.NextLink)
+	case page.LastResultSet:
+		iterator.nextURL = ""
+	case page.ContinuationURI != "":
+		iterator.nextURL, err = resolveURL(pageURL, page.ContinuationURI)
+	case page.ContinuationToken != "":
+		iterator.nextURL, err = continuationTokenURL(iterator.baseURL, page.ContinuationToken)
+	case iterator.pageSize > 0 && len(page.Value) >= iterator.pageSize:
+		iterator.skip += len(page.Value)
+		iterator.nextURL, err = iterator.skipURL()
+	default:
+		iterator.nextURL = ""
+	}
+	return err
+}
+
+// skipURL returns the base URL with the current $skip and $top, or the base URL when not paging with $skip
+func (iterator *PageIterator) skipURL() (string, error) {
+	if iterator.pageSize <= 0 && iterator.skip <= 0 {
+		return iterator.baseURL, nil
+	}
+
+	u, err := url.Parse(iterator.baseURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	if iterator.pageSize > 0 {
+		top := iterator.pageSize
+		if remaining := iterator.limit - iterator.returned - len(iterator.page); iterator.limit > 0 && remaining < top {
+			top = remaining
+		}
+		query.Set("$top", strconv.Itoa(top))
+	}
+	if iterator.skip > 0 {
+		query.Set("$skip", strconv.Itoa(iterator.skip))
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// getAllPages reads every item of the paged list at rawURL into values, which must be a pointer to a slice
+func (client *Client) getAllPages(ctx context.Context, rawURL string, pageSize int, values interface{}) error {
+	return readAllPages(client.newPageIterator(ctx, rawURL, pageSize, 0, 0), values)
+}
+
+// readAllPages reads the remaining items of the iterator into values, which must be a pointer to a slice
+func readAllPages(iterator *PageIterator, values interface{}) error {
+	var items bytes.Buffer
+	items.WriteByte('[')
+	for iterator.Next() {
+		if items.Len() > 1 {
+			items.WriteByte(',')
+		}
+		items.Write(iterator.current)
+	}
+	if err := iterator.Err(); err != nil {
+		return err
+	}
+	items.WriteByte(']')
+
+	return json.Unmarshal(items.Bytes(), values)
+}
+
+// resolveURL resolves a link returned by the service against the URL of the page it was returned in
+func resolveURL(pageURL string, link string) (string, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("Unable to parse link to next page: %v", err)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// continuationTokenURL returns the base URL requesting the results after the continuation token. Power BI expects
+// the token to be quoted
+func continuationTokenURL(baseURL string, continuationToken string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("continuationToken", "'"+continuationToken+"'")
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
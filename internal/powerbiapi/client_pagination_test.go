@@ -0,0 +1,145 @@
+package powerbiapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func writePage(w http.ResponseWriter, page map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func namedItems(from int, to int) []map[string]string {
+	items := []map[string]string{}
+	for i := from; i < to; i++ {
+		items = append(items, map[string]string{"name": fmt.Sprintf("item%d", i)})
+	}
+	return items
+}
+
+func TestGetGroups_readsEveryPageUsingSkipAndTop(t *testing.T) {
+	total := defaultPageSize*2 + 3
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		skip, _ := strconv.Atoi(r.URL.Query().Get("$skip"))
+		top, _ := strconv.Atoi(r.URL.Query().Get("$top"))
+		end := skip + top
+		if end > total {
+			end = total
+		}
+		writePage(w, map[string]interface{}{"value": namedItems(skip, end)})
+	}))
+	defer server.Close()
+	client := newTestClient(t, server.URL, nil)
+
+	groups, err := client.GetGroups("", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(groups.Value) != total || groups.Value[total-1].Name != fmt.Sprintf("item%d", total-1) {
+		t.Fatalf("expected %d groups but got %d", total, len(groups.Value))
+	}
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 pages to be requested but got %v", requests)
+	}
+}
+
+func TestGetGroups_topLimitsItemsAcrossPages(t *testing.T) {
+	var tops []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tops = append(tops, r.URL.Query().Get("$top"))
+		skip, _ := strconv.Atoi(r.URL.Query().Get("$skip"))
+		top, _ := strconv.Atoi(r.URL.Query().Get("$top"))
+		writePage(w, map[string]interface{}{"value": namedItems(skip, skip+top)})
+	}))
+	defer server.Close()
+	client := newTestClient(t, server.URL, nil)
+
+	groups, err := client.GetGroups("", defaultPageSize+10, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(groups.Value) != defaultPageSize+10 || groups.Value[0].Name != "item5" {
+		t.Fatalf("expected %d groups starting at item5 but got %d starting at %s", defaultPageSize+10, len(groups.Value), groups.Value[0].Name)
+	}
+	if strings.Join(tops, ",") != fmt.Sprintf("%d,10", defaultPageSize) {
+		t.Fatalf("expected the last page to only request the remaining items but requested %v", tops)
+	}
+}
+
+func TestGetReportsInGroup_followsNextLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			writePage(w, map[string]interface{}{"value": namedItems(0, 2), "@odata.nextLink": r.URL.Path + "?page=2"})
+		case "2":
+			writePage(w, map[string]interface{}{"value": namedItems(2, 3)})
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(t, server.URL, nil)
+
+	reports, err := client.GetReportsInGroup("group")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reports.Value) != 3 || reports.Value[2].Name != "item2" {
+		t.Fatalf("expected reports from both pages but got %+v", reports.Value)
+	}
+}
+
+func TestPageIterator_followsContinuationToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("continuationToken") {
+		case "":
+			writePage(w, map[string]interface{}{"value": namedItems(0, 2), "continuationToken": "abc=="})
+		case "'abc=='":
+			writePage(w, map[string]interface{}{"value": namedItems(2, 4), "continuationToken": "def==", "lastResultSet": true})
+		default:
+			http.Error(w, "unexpected token", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(t, server.URL, nil)
+
+	iterator := client.newPageIterator(context.Background(), client.url("/admin/activityevents"), 0, 0, 0)
+	var names []string
+	for iterator.Next() {
+		var item struct{ Name string }
+		if err := iterator.Decode(&item); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, item.Name)
+	}
+	if err := iterator.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(names, ",") != "item0,item1,item2,item3" {
+		t.Fatalf("expected items from both pages but got %v", names)
+	}
+}
+
+func TestPageIterator_stopsWhenNextLinkDoesNotAdvance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writePage(w, map[string]interface{}{"value": namedItems(0, 1), "@odata.nextLink": r.URL.String()})
+	}))
+	defer server.Close()
+	client := newTestClient(t, server.URL, nil)
+
+	_, err := client.GetGateways()
+	if err == nil || !strings.Contains(err.Error(), "Paging did not advance") {
+		t.Fatalf("expected paging to stop with an error but got %v", err)
+	}
+}
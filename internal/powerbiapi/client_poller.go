@@ -0,0 +1,127 @@
+package powerbiapi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// PollOptions represents how often a long running operation is checked while waiting for it to complete
+type PollOptions struct {
+	// Interval is the delay before checking again after the first check
+	Interval time.Duration
+	// Multiplier increases the delay after every check, a multiplier of 1 checks at a fixed interval
+	Multiplier float64
+	// MaxInterval caps the delay between checks
+	MaxInterval time.Duration
+}
+
+// DefaultPollOptions returns the poll options used when none are specified
+func DefaultPollOptions() PollOptions {
+	return PollOptions{
+		Interval:    1 * time.Second,
+		Multiplier:  1.5,
+		MaxInterval: 10 * time.Second,
+	}
+}
+
+// withDefaults fills any unset values with the values from DefaultPollOptions
+func (options PollOptions) withDefaults() PollOptions {
+	defaults := DefaultPollOptions()
+	if options.Interval <= 0 {
+		options.Interval = defaults.Interval
+	}
+	if options.Multiplier < 1 {
+		options.Multiplier = defaults.Multiplier
+	}
+	if options.MaxInterval <= 0 {
+		options.MaxInterval = defaults.MaxInterval
+	}
+	if options.MaxInterval < options.Interval {
+		options.MaxInterval = options.Interval
+	}
+	return options
+}
+
+// Poller waits for a long running operation, such as an import or refresh, to reach a terminal state.
+// States that are neither succeeded nor transient are treated as failures
+type Poller struct {
+	// Operation describes what is being waited for in logs and errors, such as "Import"
+	Operation string
+	// SucceededStates are the terminal states that mean the operation completed successfully
+	SucceededStates []string
+	// TransientStates are the states that mean the operation is still in progress
+	TransientStates []string
+	// Options determines how often the operation is checked
+	Options PollOptions
+
+	sleep func(ctx context.Context, d time.Duration) error
+	now   func() time.Time
+}
+
+// Poll calls check until it returns a terminal state, the context is done or check fails. The last state is returned,
+// with an error if the state is not a succeeded state
+func (poller Poller) Poll(ctx context.Context, check func(ctx context.Context) (string, error)) (string, error) {
+	options := poller.Options.withDefaults()
+	sleep, now := poller.sleep, poller.now
+	if sleep == nil {
+		sleep = sleepWithContext
+	}
+	if now == nil {
+		now = time.Now
+	}
+
+	started := now()
+	interval := options.Interval
+	for {
+		state, err := check(ctx)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return state, poller.newTimeoutError(ctx, started, now)
+			}
+			return state, err
+		}
+
+		elapsed := now().Sub(started).Round(time.Second)
+		if containsStateFold(poller.SucceededStates, state) {
+			log.Printf("[DEBUG] %s completed with state '%s' after %v", poller.Operation, state, elapsed)
+			return state, nil
+		}
+		if !containsStateFold(poller.TransientStates, state) {
+			log.Printf("[DEBUG] %s completed with state '%s' after %v", poller.Operation, state, elapsed)
+			return state, fmt.Errorf("%s completed with invalid state '%s'", poller.Operation, state)
+		}
+
+		log.Printf("[DEBUG] %s is in state '%s' after %v, checking again in %v", poller.Operation, state, elapsed, interval)
+		if err := sleep(ctx, interval); err != nil {
+			if err == context.DeadlineExceeded {
+				return state, poller.newTimeoutError(ctx, started, now)
+			}
+			return state, err
+		}
+
+		interval = time.Duration(float64(interval) * options.Multiplier)
+		if interval > options.MaxInterval {
+			interval = options.MaxInterval
+		}
+	}
+}
+
+func (poller Poller) newTimeoutError(ctx context.Context, started time.Time, now func() time.Time) error {
+	timeout := now().Sub(started)
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = deadline.Sub(started)
+	}
+	return fmt.Errorf("Timed out waiting for %s to complete. %s taking longer than %v seconds", strings.ToLower(poller.Operation), poller.Operation, timeout.Round(time.Second).Seconds())
+}
+
+func containsStateFold(states []string, state string) bool {
+	for _, s := range states {
+		if strings.EqualFold(s, state) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package powerbiapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestPoller(slept *[]time.Duration) Poller {
+	now := time.Unix(0, 0)
+	return Poller{
+		Operation:       "Refresh",
+		SucceededStates: []string{"Completed"},
+		TransientStates: []string{"NotStarted", "Unknown"},
+		Options: PollOptions{
+			Interval:    time.Second,
+			Multiplier:  2,
+			MaxInterval: 5 * time.Second,
+		},
+		now: func() time.Time { return now },
+		sleep: func(ctx context.Context, d time.Duration) error {
+			*slept = append(*slept, d)
+			now = now.Add(d)
+			return nil
+		},
+	}
+}
+
+func TestPoller_backsOffUntilSucceeded(t *testing.T) {
+	var slept []time.Duration
+	poller := newTestPoller(&slept)
+
+	states := []string{"NotStarted", "Unknown", "Unknown", "Unknown", "Unknown", "completed"}
+	checks := 0
+	state, err := poller.Poll(context.Background(), func(ctx context.Context) (string, error) {
+		checks++
+		return states[checks-1], nil
+	})
+
+	if err != nil || state != "completed" {
+		t.Fatalf("expected the operation to complete but got state '%s' and error %v", state, err)
+	}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	if len(slept) != len(expected) {
+		t.Fatalf("expected to wait %v but waited %v", expected, slept)
+	}
+	for i := range expected {
+		if slept[i] != expected[i] {
+			t.Fatalf("expected to wait %v but waited %v", expected, slept)
+		}
+	}
+}
+
+func TestPoller_failsOnUnknownTerminalState(t *testing.T) {
+	var slept []time.Duration
+	poller := newTestPoller(&slept)
+
+	state, err := poller.Poll(context.Background(), func(ctx context.Context) (string, error) {
+		return "Failed", nil
+	})
+
+	if state != "Failed" || err == nil || err.Error() != "Refresh completed with invalid state 'Failed'" {
+		t.Fatalf("expected the failed state to be an error but got state '%s' and error %v", state, err)
+	}
+}
+
+func TestPoller_returnsCheckErrors(t *testing.T) {
+	var slept []time.Duration
+	poller := newTestPoller(&slept)
+
+	checkErr := errors.New("check failed")
+	_, err := poller.Poll(context.Background(), func(ctx context.Context) (string, error) {
+		return "", checkErr
+	})
+
+	if err != checkErr {
+		t.Fatalf("expected the check error but got %v", err)
+	}
+}
+
+func TestPoller_reportsTimeout(t *testing.T) {
+	poller := Poller{
+		Operation:       "Import",
+		SucceededStates: []string{"Succeeded"},
+		TransientStates: []string{"Publishing"},
+		Options:         PollOptions{Interval: time.Millisecond},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	state, err := poller.Poll(ctx, func(ctx context.Context) (string, error) {
+		return "Publishing", nil
+	})
+
+	if state != "Publishing" || err == nil || !strings.HasPrefix(err.Error(), "Timed out waiting for import to complete. Import taking longer than") {
+		t.Fatalf("expected a timeout error but got state '%s' and error %v", state, err)
+	}
+}
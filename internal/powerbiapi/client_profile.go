@@ -0,0 +1,46 @@
+package powerbiapi
+
+import (
+	"context"
+	"net/http"
+)
+
+// profileIDHeader makes a service principal act as one of its service principal profiles
+const profileIDHeader = "X-PowerBI-Profile-Id"
+
+type profileIDContextKey struct{}
+
+// WithProfileID returns a context whose requests are made as the service principal profile, overriding the
+// client's default profile. An empty profile ID makes requests as the service principal itself
+func WithProfileID(ctx context.Context, profileID string) context.Context {
+	return context.WithValue(ctx, profileIDContextKey{}, profileID)
+}
+
+// profileID returns the profile requests made with the context are made as
+func (client *Client) profileID(ctx context.Context) string {
+	if profileID, ok := ctx.Value(profileIDContextKey{}).(string); ok {
+		return profileID
+	}
+	return client.defaultProfileID
+}
+
+type profileRoundTripper struct {
+	innerRoundTripper http.RoundTripper
+	client            *Client
+}
+
+// newProfileRoundTripper sends the profile ID header on requests that are made as a service principal profile
+func newProfileRoundTripper(client *Client, next http.RoundTripper) http.RoundTripper {
+	return &profileRoundTripper{
+		innerRoundTripper: next,
+		client:            client,
+	}
+}
+
+func (rt *profileRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if profileID := rt.client.profileID(req.Context()); profileID != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(profileIDHeader, profileID)
+	}
+	return rt.innerRoundTripper.RoundTrip(req)
+}
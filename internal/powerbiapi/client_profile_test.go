@@ -0,0 +1,78 @@
+package powerbiapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// describeProfileRequest describes a request along with the profile ID header it was sent with
+func describeProfileRequest(r *http.Request) string {
+	return r.Method + " " + r.URL.Path + " " + r.Header.Get(profileIDHeader)
+}
+
+func TestProfileID_defaultCanBeOverriddenByContext(t *testing.T) {
+	var requests []string
+	server := newRecordingTestServer(&requests, describeProfileRequest)
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(options *ClientOptions) {
+		options.ProfileID = "default-profile"
+	})
+
+	if _, err := client.GetCapacities(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetCapacitiesWithContext(WithProfileID(context.Background(), "other-profile")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetCapacitiesWithContext(WithProfileID(context.Background(), "")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetProfiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"GET /v1.0/myorg/capacities default-profile",
+		"GET /v1.0/myorg/capacities other-profile",
+		"GET /v1.0/myorg/capacities ",
+		"GET /v1.0/myorg/profiles ",
+	}
+	if strings.Join(requests, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected requests %v but got %v", expected, requests)
+	}
+}
+
+func TestProfileID_responsesAreCachedPerProfile(t *testing.T) {
+	var requests []string
+	server := newRecordingTestServer(&requests, describeProfileRequest)
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(options *ClientOptions) {
+		options.Cache.TTL = time.Hour
+	})
+
+	profileCtx := WithProfileID(context.Background(), "profile")
+	for _, ctx := range []context.Context{context.Background(), profileCtx, context.Background(), profileCtx} {
+		if _, err := client.GetGroupWithContext(ctx, "group"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected one request for each profile but got %v", requests)
+	}
+
+	// invalidating a key clears the responses of every profile
+	client.cache.invalidate(groupsCacheKey)
+	for _, ctx := range []context.Context{context.Background(), profileCtx} {
+		if _, err := client.GetGroupWithContext(ctx, "group"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(requests) != 4 {
+		t.Fatalf("expected every profile to request again after invalidation but got %v", requests)
+	}
+}
@@ -0,0 +1,356 @@
+package powerbiapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CassetteMode determines whether a Recorder sends requests and records them, or replays recorded responses
+type CassetteMode string
+
+const (
+	// CassetteModeRecord sends requests to Power BI and saves the sanitised interactions to the cassette
+	CassetteModeRecord CassetteMode = "record"
+	// CassetteModeReplay responds with interactions from the cassette and never sends requests
+	CassetteModeReplay CassetteMode = "replay"
+)
+
+// PlaceholderTenantID replaces tenant IDs in cassettes
+const PlaceholderTenantID = "00000000-0000-0000-0000-000000000000"
+
+// recordedResponseHeaders are the only response headers kept in cassettes, other headers are not needed
+// by the client and may identify the tenant
+var recordedResponseHeaders = []string{"Content-Type", "Location", "Retry-After", "RequestId"}
+
+var (
+	emailRegex       = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	tokenTenantRegex = regexp.MustCompile(`/([^/?]+)/oauth2/`)
+)
+
+// Cassette is the sanitised interactions saved by a Recorder
+type Cassette struct {
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+// CassetteInteraction is a recorded request and the response it received
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is a recorded request. Requests are matched on method, URL path and query, and body.
+// Multipart and binary bodies are recorded as a hash of their content
+type CassetteRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// CassetteResponse is a recorded response
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder captures Power BI interactions to a cassette file, or replays them from one, so tests can run
+// without network access. Tokens, secrets, tenant IDs and email addresses are scrubbed before they are saved
+type Recorder struct {
+	mode     CassetteMode
+	path     string
+	mux      sync.Mutex
+	cassette Cassette
+	replayed []bool
+
+	// replacements are sensitive values and the placeholders that replace them
+	replacements map[string]string
+	emailCount   int
+}
+
+// NewRecorder creates a recorder for the cassette at path. In replay mode the cassette must already exist
+func NewRecorder(path string, mode CassetteMode) (*Recorder, error) {
+	recorder := &Recorder{
+		mode:         mode,
+		path:         path,
+		replacements: map[string]string{},
+	}
+
+	switch mode {
+	case CassetteModeRecord:
+	case CassetteModeReplay:
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read cassette: %v", err)
+		}
+		if err := json.Unmarshal(data, &recorder.cassette); err != nil {
+			return nil, fmt.Errorf("Unable to read cassette %s: %v", path, err)
+		}
+		recorder.replayed = make([]bool, len(recorder.cassette.Interactions))
+	default:
+		return nil, fmt.Errorf("Unknown cassette mode '%s'. Expected one of %s, %s", mode, CassetteModeRecord, CassetteModeReplay)
+	}
+
+	return recorder, nil
+}
+
+// AddReplacement scrubs a sensitive value from recorded interactions by replacing it with the placeholder.
+// Tokens, tenant IDs and email addresses are scrubbed without needing to be added
+func (recorder *Recorder) AddReplacement(value string, placeholder string) {
+	if value == "" || value == placeholder {
+		return
+	}
+	recorder.mux.Lock()
+	defer recorder.mux.Unlock()
+	recorder.replacements[value] = placeholder
+}
+
+// Save writes the recorded interactions to the cassette. Nothing is written when replaying
+func (recorder *Recorder) Save() error {
+	if recorder.mode != CassetteModeRecord {
+		return nil
+	}
+
+	// bodies are left unescaped so cassettes are readable in diffs
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	recorder.mux.Lock()
+	err := encoder.Encode(recorder.cassette)
+	recorder.mux.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(recorder.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(recorder.path, data.Bytes(), 0644)
+}
+
+// roundTripper records or replays the requests that would be sent by next
+func (recorder *Recorder) roundTripper(next http.RoundTripper) http.RoundTripper {
+	return &recorderRoundTripper{
+		innerRoundTripper: next,
+		recorder:          recorder,
+	}
+}
+
+type recorderRoundTripper struct {
+	innerRoundTripper http.RoundTripper
+	recorder          *Recorder
+}
+
+func (rt *recorderRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := rt.recorder
+
+	body, req, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder.mux.Lock()
+	recorder.learnTenant(req.URL)
+	request := CassetteRequest{
+		Method: req.Method,
+		URL:    recorder.scrubURL(req.URL),
+		Body:   recorder.scrubRequestBody(req.Header.Get("Content-Type"), body),
+	}
+	recorder.mux.Unlock()
+
+	if recorder.mode == CassetteModeReplay {
+		return recorder.replay(req, request)
+	}
+
+	resp, err := rt.innerRoundTripper.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+	if err != nil {
+		return resp, err
+	}
+
+	recorder.mux.Lock()
+	defer recorder.mux.Unlock()
+
+	header := http.Header{}
+	for _, name := range recordedResponseHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			header.Set(name, recorder.scrub(value))
+		}
+	}
+	recorder.cassette.Interactions = append(recorder.cassette.Interactions, CassetteInteraction{
+		Request: request,
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       recorder.scrub(redactBody(resp.Header.Get("Content-Type"), responseBody)),
+		},
+	})
+
+	return resp, nil
+}
+
+// replay responds with the first recorded interaction matching the request that has not been replayed. If every
+// matching interaction has been replayed the last one is replayed again, so extra polls and token requests still succeed
+func (recorder *Recorder) replay(req *http.Request, request CassetteRequest) (*http.Response, error) {
+	recorder.mux.Lock()
+	defer recorder.mux.Unlock()
+
+	match := -1
+	for i, interaction := range recorder.cassette.Interactions {
+		if interaction.Request != request {
+			continue
+		}
+		match = i
+		if !recorder.replayed[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("No interaction in cassette %s matches %s %s", recorder.path, request.Method, request.URL)
+	}
+	recorder.replayed[match] = true
+
+	recorded := recorder.cassette.Interactions[match].Response
+	header := http.Header{}
+	for name, values := range recorded.Header {
+		header[name] = append([]string(nil), values...)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// readRequestBody reads the request body, returning a request that sends the same body from memory. The body is
+// never read through GetBody as it may share a file with the body
+func readRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+
+	data, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.ContentLength = int64(len(data))
+	return data, req, nil
+}
+
+// learnTenant adds the tenant from token requests as a replacement, so it is scrubbed wherever else it appears
+func (recorder *Recorder) learnTenant(u *url.URL) {
+	if match := tokenTenantRegex.FindStringSubmatch(u.Path); match != nil && match[1] != PlaceholderTenantID {
+		recorder.replacements[match[1]] = PlaceholderTenantID
+	}
+}
+
+// scrubURL returns the path and query of the URL with sensitive values scrubbed
+func (recorder *Recorder) scrubURL(u *url.URL) string {
+	redactedURL, err := url.Parse(redactURL(u))
+	if err != nil {
+		return recorder.scrub(u.RequestURI())
+	}
+	return recorder.scrub(redactedURL.RequestURI())
+}
+
+// scrubRequestBody returns the body used to match the request. Multipart and binary bodies are hashed as they
+// are large and multipart boundaries change on every request
+func (recorder *Recorder) scrubRequestBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		hash := sha256.New()
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			io.Copy(hash, part)
+		}
+		return "sha256:" + hex.EncodeToString(hash.Sum(nil))
+	case !isBodyLoggable(contentType):
+		hash := sha256.Sum256(body)
+		return "sha256:" + hex.EncodeToString(hash[:])
+	case mediaType == "application/x-www-form-urlencoded":
+		// values are scrubbed before encoding, otherwise encoded email addresses would not be found
+		if values, err := url.ParseQuery(redactBody(contentType, body)); err == nil {
+			for name := range values {
+				for i := range values[name] {
+					values[name][i] = recorder.scrub(values[name][i])
+				}
+			}
+			return values.Encode()
+		}
+	}
+
+	return recorder.scrub(redactBody(contentType, body))
+}
+
+// scrub replaces sensitive values with placeholders. Email addresses are replaced with numbered example.com
+// addresses, addresses that are already at example.com are assumed to be placeholders
+func (recorder *Recorder) scrub(value string) string {
+	values := make([]string, 0, len(recorder.replacements))
+	for sensitiveValue := range recorder.replacements {
+		values = append(values, sensitiveValue)
+	}
+	// replace longer values first so values containing other values are replaced whole
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+	for _, sensitiveValue := range values {
+		value = strings.Replace(value, sensitiveValue, recorder.replacements[sensitiveValue], -1)
+	}
+
+	return emailRegex.ReplaceAllStringFunc(value, func(email string) string {
+		if strings.HasSuffix(strings.ToLower(email), "@example.com") {
+			return email
+		}
+		recorder.emailCount++
+		placeholder := fmt.Sprintf("user%d@example.com", recorder.emailCount)
+		recorder.replacements[email] = placeholder
+		return placeholder
+	})
+}
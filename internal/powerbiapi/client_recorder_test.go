@@ -0,0 +1,118 @@
+package powerbiapi
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder_recordsScrubbedInteractionsAndReplaysThem(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassettes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cassettePath := filepath.Join(dir, "cassette.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret-cookie")
+		if strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token") {
+			w.Write([]byte(`{"access_token":"real-access-token","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(`{"value":[{"identifier":"jane.doe@contoso.com","emailAddress":"jane.doe@contoso.com","principalType":"User"}],"tenant":"my-tenant-id"}`))
+	}))
+
+	recorder, err := NewRecorder(cassettePath, CassetteModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordingClient := newClientCredentialTestClient(t, server.URL, func(options *ClientOptions) {
+		options.Recorder = recorder
+	})
+	recorded, err := recordingClient.GetGroupUsers("group-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded.Value[0].EmailAddress != "jane.doe@contoso.com" {
+		t.Fatalf("expected the client to receive the unscrubbed response when recording but was %+v", recorded.Value[0])
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	data, err := ioutil.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sensitiveValue := range []string{"real-access-token", testTenantID, testClientSecret, "jane.doe@contoso.com", "secret-cookie"} {
+		if strings.Contains(string(data), sensitiveValue) {
+			t.Fatalf("expected %s to be scrubbed from cassette:\n%s", sensitiveValue, data)
+		}
+	}
+
+	// the server is closed so responses can only come from the cassette
+	replayer, err := NewRecorder(cassettePath, CassetteModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayingClient := newClientCredentialTestClient(t, server.URL, func(options *ClientOptions) {
+		options.Recorder = replayer
+	})
+	replayed, err := replayingClient.GetGroupUsers("group-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.Value[0].EmailAddress != "user1@example.com" || replayed.Value[0].PrincipalType != "User" {
+		t.Fatalf("expected the scrubbed response to be replayed but was %+v", replayed.Value[0])
+	}
+
+	_, err = replayingClient.GetGroupUsers("other-group-id")
+	if err == nil || !strings.Contains(err.Error(), "No interaction in cassette") {
+		t.Fatalf("expected an unrecorded request to fail but got %v", err)
+	}
+}
+
+func TestRecorder_replaysMatchingInteractionsInOrder(t *testing.T) {
+	recorder := &Recorder{
+		mode: CassetteModeReplay,
+		cassette: Cassette{Interactions: []CassetteInteraction{
+			{Request: CassetteRequest{Method: "GET", URL: "/v1.0/myorg/groups/g/imports/i"}, Response: CassetteResponse{StatusCode: 200, Body: `{"importState":"Publishing"}`}},
+			{Request: CassetteRequest{Method: "POST", URL: "/v1.0/myorg/groups/g/datasets/d/Default.UpdateParameters", Body: `{"a":1}`}, Response: CassetteResponse{StatusCode: 200}},
+			{Request: CassetteRequest{Method: "GET", URL: "/v1.0/myorg/groups/g/imports/i"}, Response: CassetteResponse{StatusCode: 200, Body: `{"importState":"Succeeded"}`}},
+		}},
+		replayed:     make([]bool, 3),
+		replacements: map[string]string{},
+	}
+	rt := recorder.roundTripper(nil)
+
+	var states []string
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest("GET", "https://api.powerbi.com/v1.0/myorg/groups/g/imports/i", nil)
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		states = append(states, string(body))
+	}
+
+	expected := []string{`{"importState":"Publishing"}`, `{"importState":"Succeeded"}`, `{"importState":"Succeeded"}`}
+	for i := range expected {
+		if states[i] != expected[i] {
+			t.Fatalf("expected responses %v but got %v", expected, states)
+		}
+	}
+
+	req, _ := http.NewRequest("POST", "https://api.powerbi.com/v1.0/myorg/groups/g/datasets/d/Default.UpdateParameters", strings.NewReader(`{"a":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	if _, err := rt.RoundTrip(req); err == nil {
+		t.Fatal("expected a request with a different body not to match")
+	}
+}
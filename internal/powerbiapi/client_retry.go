@@ -1,62 +1,192 @@
 package powerbiapi
 
 import (
-	"fmt"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math/rand"
 	"net/http"
 	"strconv"
 	"time"
 )
 
-type retryTooManyRequestsRoundTripper struct {
+// RetryOptions represents how failed Power BI API requests are retried
+type RetryOptions struct {
+	// MaxRetries is the number of times a request is retried after the initial attempt
+	MaxRetries int
+	// MinBackoff is the base delay before the first retry, it doubles on every subsequent retry
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between retries. Retry-After headers sent by the service are always honoured
+	MaxBackoff time.Duration
+	// RetryableStatusCodes are the HTTP status codes that are always retried
+	RetryableStatusCodes []int
+	// RetryableErrorCodes are the Power BI error codes that are retried regardless of the HTTP status code
+	RetryableErrorCodes []string
+}
+
+// DefaultRetryOptions returns the retry options used when none are specified
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{
+		MaxRetries: 4,
+		MinBackoff: 1 * time.Second,
+		MaxBackoff: 30 * time.Second,
+		// PowerBI API is prone to throttling and intermittent 5xx errors that succeed on retry
+		RetryableStatusCodes: []int{429, 500, 502, 503, 504},
+		RetryableErrorCodes:  append([]string(nil), transientErrorCodes...),
+	}
+}
+
+// withDefaults fills any unset values with the values from DefaultRetryOptions
+func (options RetryOptions) withDefaults() RetryOptions {
+	defaults := DefaultRetryOptions()
+	if options.MaxRetries < 0 {
+		options.MaxRetries = 0
+	}
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = defaults.MinBackoff
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = defaults.MaxBackoff
+	}
+	if options.MaxBackoff < options.MinBackoff {
+		options.MaxBackoff = options.MinBackoff
+	}
+	if options.RetryableStatusCodes == nil {
+		options.RetryableStatusCodes = defaults.RetryableStatusCodes
+	}
+	if options.RetryableErrorCodes == nil {
+		options.RetryableErrorCodes = defaults.RetryableErrorCodes
+	}
+	return options
+}
+
+type retryRoundTripper struct {
 	innerRoundTripper http.RoundTripper
+	options           RetryOptions
+	sleep             func(ctx context.Context, d time.Duration) error
 }
 
-func newRetryTooManyRequestsRoundTripper(next http.RoundTripper) http.RoundTripper {
-	return &retryTooManyRequestsRoundTripper{
+func newRetryRoundTripper(options RetryOptions, next http.RoundTripper) http.RoundTripper {
+	return &retryRoundTripper{
 		innerRoundTripper: next,
+		options:           options.withDefaults(),
+		sleep:             sleepWithContext,
 	}
 }
 
-func (rt *retryTooManyRequestsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+func (rt *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	resp, err := rt.innerRoundTripper.RoundTrip(req)
 
-retry:
-	for attempts := 1; err == nil && resp.StatusCode == 429; attempts++ {
-		switch attempts {
-		case 1:
-			// retry immediately. PowerBI API typically responds successfully on a retry
+	for attempt := 0; attempt < rt.options.MaxRetries && err == nil && rt.isRetryable(resp); attempt++ {
+
+		// the body of the previous attempt has been consumed, we can only retry if we can send it again
+		if !isReplayable(req) {
+			break
+		}
+		retryRequest, rewindErr := rewindRequest(req)
+		if rewindErr != nil {
 			break
-		case 2:
-			// if failed again then we will respect the retry-after header
-			// these can unfortunately be anywhere up to a minute
-			time.Sleep(readRetryAfter(resp, 5*time.Second))
-		case 3:
-			// respect retry-after again
-			time.Sleep(readRetryAfter(resp, 10*time.Second))
-		default:
-			// we have retried enough
-			break retry
 		}
 
-		resp, err = rt.innerRoundTripper.RoundTrip(req)
+		backoff := rt.backoff(attempt, resp)
+		log.Printf("[DEBUG] Retrying %s %s in %v after status '%s' (retry %d of %d)", req.Method, redactURL(req.URL), backoff, resp.Status, attempt+1, rt.options.MaxRetries)
+		if sleepErr := rt.sleep(req.Context(), backoff); sleepErr != nil {
+			closeResponse(resp)
+			return nil, sleepErr
+		}
+		closeResponse(resp)
+
+		resp, err = rt.innerRoundTripper.RoundTrip(retryRequest)
 	}
 
 	return resp, err
 }
 
-func readRetryAfter(resp *http.Response, fallback time.Duration) time.Duration {
-	waitSeconds, parseErr := extractHeaderAsInteger(resp, "Retry-After")
-	if parseErr != nil {
-		return fallback
+// isRetryable determines if the response is a transient failure. The body is
+// buffered so the error code can be inspected while still being readable later
+func (rt *retryRoundTripper) isRetryable(resp *http.Response) bool {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false
+	}
+
+	for _, statusCode := range rt.options.RetryableStatusCodes {
+		if resp.StatusCode == statusCode {
+			return true
+		}
+	}
+
+	if len(rt.options.RetryableErrorCodes) == 0 || resp.Body == nil || resp.Body == http.NoBody {
+		return false
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return false
+	}
+
+	var errorResponse ErrorResponse
+	if json.Unmarshal(data, &errorResponse) != nil {
+		return false
+	}
+	for _, errorCode := range rt.options.RetryableErrorCodes {
+		if errorResponse.Error.Code == errorCode {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff determines how long to wait before the next attempt. Retry-After is honoured when
+// present, otherwise exponential backoff with jitter is used so parallel requests spread out
+func (rt *retryRoundTripper) backoff(attempt int, resp *http.Response) time.Duration {
+	if retryAfter, ok := readRetryAfter(resp); ok {
+		return retryAfter
+	}
+
+	backoff := rt.options.MinBackoff << uint(attempt)
+	if backoff > rt.options.MaxBackoff || backoff <= 0 {
+		backoff = rt.options.MaxBackoff
+	}
+
+	// equal jitter, wait at least half the backoff and a random amount of the rest
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func readRetryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
 	}
-	return time.Duration(waitSeconds+1) * time.Second
+
+	if waitSeconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(waitSeconds+1) * time.Second, true
+	}
+
+	if retryAt, err := http.ParseTime(value); err == nil {
+		wait := time.Until(retryAt)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
 }
 
-func extractHeaderAsInteger(resp *http.Response, key string) (int, error) {
-	headerValues, ok := resp.Header[key]
-	if !ok || len(headerValues) == 0 {
-		return 0, fmt.Errorf("Response does not contain key '%s'", key)
+// sleepWithContext waits for the duration, returning early with the context error if the context is done first
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return strconv.Atoi(headerValues[0])
 }
@@ -0,0 +1,169 @@
+package powerbiapi
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryRoundTripper(t *testing.T) {
+	testCases := []struct {
+		name             string
+		statusCode       int
+		body             string
+		retryAfter       string
+		expectedAttempts int
+		expectedSleeps   []time.Duration
+	}{
+		{
+			name:             "throttled requests honour retry after",
+			statusCode:       429,
+			retryAfter:       "7",
+			expectedAttempts: 4,
+			expectedSleeps:   []time.Duration{8 * time.Second, 8 * time.Second, 8 * time.Second},
+		},
+		{
+			name:             "validation errors are not retried",
+			statusCode:       400,
+			body:             `{"error":{"code":"InvalidRequest","message":"bad"}}`,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "retryable error codes are retried",
+			statusCode:       400,
+			body:             `{"error":{"code":"ServiceUnavailable"}}`,
+			expectedAttempts: 4,
+		},
+		{
+			name:             "not found is not retried",
+			statusCode:       404,
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if testCase.retryAfter != "" {
+					w.Header().Set("Retry-After", testCase.retryAfter)
+				}
+				w.WriteHeader(testCase.statusCode)
+				w.Write([]byte(testCase.body))
+			}))
+			defer server.Close()
+
+			var sleeps []time.Duration
+			rt := newRetryRoundTripper(RetryOptions{
+				MaxRetries: 3,
+				MinBackoff: time.Second,
+				MaxBackoff: 4 * time.Second,
+			}, http.DefaultTransport).(*retryRoundTripper)
+			rt.sleep = func(ctx context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			}
+
+			req, _ := http.NewRequest("GET", server.URL, nil)
+			resp, err := rt.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if attempts != testCase.expectedAttempts {
+				t.Fatalf("expected %d attempts but got %d", testCase.expectedAttempts, attempts)
+			}
+			if testCase.expectedSleeps != nil && len(sleeps) != len(testCase.expectedSleeps) {
+				t.Fatalf("expected sleeps %v but got %v", testCase.expectedSleeps, sleeps)
+			}
+			for i := range testCase.expectedSleeps {
+				if sleeps[i] != testCase.expectedSleeps[i] {
+					t.Fatalf("expected sleeps %v but got %v", testCase.expectedSleeps, sleeps)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryRoundTripper_backoffIsBoundedWithJitter(t *testing.T) {
+	rt := newRetryRoundTripper(RetryOptions{
+		MaxRetries: 10,
+		MinBackoff: time.Second,
+		MaxBackoff: 8 * time.Second,
+	}, http.DefaultTransport).(*retryRoundTripper)
+
+	resp := &http.Response{Header: http.Header{}}
+	for attempt := 0; attempt < 10; attempt++ {
+		expected := time.Second << uint(attempt)
+		if expected > 8*time.Second {
+			expected = 8 * time.Second
+		}
+
+		backoff := rt.backoff(attempt, resp)
+		if backoff < expected/2 || backoff > expected {
+			t.Fatalf("attempt %d: expected backoff between %v and %v but got %v", attempt, expected/2, expected, backoff)
+		}
+	}
+}
+
+func TestRetryRoundTripper_resendsRequestBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.WriteHeader(503)
+			return
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	rt := newRetryRoundTripper(RetryOptions{MaxRetries: 3}, http.DefaultTransport).(*retryRoundTripper)
+	rt.sleep = func(context.Context, time.Duration) error { return nil }
+
+	jsonRequest, _ := newJSONRequest(context.Background(), "POST", server.URL, map[string]string{"name": "workspace"})
+	multipartRequest, _ := newMultipartRequest(context.Background(), "POST", server.URL, strings.NewReader("pbix content"))
+
+	for _, req := range []*http.Request{jsonRequest, multipartRequest} {
+		bodies = nil
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if len(bodies) != 3 || bodies[0] == "" || bodies[1] != bodies[0] || bodies[2] != bodies[0] {
+			t.Fatalf("expected the same body to be sent on every attempt but got %q", bodies)
+		}
+	}
+}
+
+func TestRetryRoundTripper_doesNotRetryUnreplayableBody(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(503)
+	}))
+	defer server.Close()
+
+	rt := newRetryRoundTripper(RetryOptions{MaxRetries: 3}, http.DefaultTransport).(*retryRoundTripper)
+	rt.sleep = func(context.Context, time.Duration) error { return nil }
+
+	req, _ := http.NewRequest("POST", server.URL, ioutil.NopCloser(strings.NewReader("stream")))
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if attempts != 1 {
+		t.Fatalf("expected a request that cannot be replayed to be sent once but was sent %d times", attempts)
+	}
+}
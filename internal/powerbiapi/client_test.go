@@ -0,0 +1,91 @@
+package powerbiapi
+
+import (
+	"context"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewMultipartRequest_streamsFileWithContentLength(t *testing.T) {
+	file, err := ioutil.TempFile("", "powerbi-*.pbix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	content := strings.Repeat("pbix content ", 1000)
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := newMultipartRequest(context.Background(), "POST", "https://api.powerbi.com", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if attempt > 0 {
+			req, err = rewindRequest(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int64(len(body)) != req.ContentLength {
+			t.Fatalf("attempt %d: expected content length %d to match body length %d", attempt, req.ContentLength, len(body))
+		}
+
+		_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		part, err := multipart.NewReader(strings.NewReader(string(body)), params["boundary"]).NextPart()
+		if err != nil {
+			t.Fatal(err)
+		}
+		partContent, _ := ioutil.ReadAll(part)
+		if string(partContent) != content {
+			t.Fatalf("attempt %d: multipart content did not match the file", attempt)
+		}
+	}
+}
+
+func TestNewMultipartRequest_unseekableReaderIsNotReplayable(t *testing.T) {
+	req, err := newMultipartRequest(context.Background(), "POST", "https://api.powerbi.com", ioutil.NopCloser(strings.NewReader("stream")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isReplayable(req) {
+		t.Fatal("expected a request streamed from an unseekable reader to not be replayable")
+	}
+}
+
+func TestGroupURL_myWorkspaceIsAddressedWithoutGroup(t *testing.T) {
+	client := &Client{endpoints: Endpoints{APIURL: "https://api.powerbi.com"}}
+
+	tests := map[string]string{
+		"":          "https://api.powerbi.com/v1.0/myorg/datasets/dataset%20id",
+		"me":        "https://api.powerbi.com/v1.0/myorg/datasets/dataset%20id",
+		"ME":        "https://api.powerbi.com/v1.0/myorg/datasets/dataset%20id",
+		"group/%id": "https://api.powerbi.com/v1.0/myorg/groups/group%2F%25id/datasets/dataset%20id",
+		"1234-abcd": "https://api.powerbi.com/v1.0/myorg/groups/1234-abcd/datasets/dataset%20id",
+	}
+	for groupID, expected := range tests {
+		if actual := client.groupURL(groupID, "/datasets/%s", url.PathEscape("dataset id")); actual != expected {
+			t.Errorf("expected workspace '%s' to have URL %s but was %s", groupID, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package powerbiapi
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ThrottleOptions represents how the client limits the requests it sends so it throttles itself
+// before the Power BI service does. A value of zero means unlimited
+type ThrottleOptions struct {
+	// MaxRequestsPerMinute is the sustained rate of requests, short bursts of up to a second's worth of requests are allowed
+	MaxRequestsPerMinute int
+	// MaxConcurrentRequests is the number of requests that can be in flight at the same time
+	MaxConcurrentRequests int
+}
+
+type throttleRoundTripper struct {
+	innerRoundTripper http.RoundTripper
+	limiter           *tokenBucket
+	inFlight          chan struct{}
+}
+
+// newThrottleRoundTripper limits the rate and concurrency of requests. If neither are limited the next round tripper is returned as is
+func newThrottleRoundTripper(options ThrottleOptions, next http.RoundTripper) http.RoundTripper {
+	if options.MaxRequestsPerMinute <= 0 && options.MaxConcurrentRequests <= 0 {
+		return next
+	}
+
+	rt := &throttleRoundTripper{
+		innerRoundTripper: next,
+	}
+	if options.MaxRequestsPerMinute > 0 {
+		rt.limiter = newTokenBucket(options.MaxRequestsPerMinute)
+	}
+	if options.MaxConcurrentRequests > 0 {
+		rt.inFlight = make(chan struct{}, options.MaxConcurrentRequests)
+	}
+	return rt
+}
+
+func (rt *throttleRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if rt.inFlight != nil {
+		select {
+		case rt.inFlight <- struct{}{}:
+			defer func() { <-rt.inFlight }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if rt.limiter != nil {
+		if err := rt.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return rt.innerRoundTripper.RoundTrip(req)
+}
+
+// tokenBucket allows requests at a sustained rate, tokens are refilled continuously up to the capacity of the bucket
+type tokenBucket struct {
+	mux             sync.Mutex
+	capacity        float64
+	tokens          float64
+	refillPerSecond float64
+	last            time.Time
+	now             func() time.Time
+	sleep           func(ctx context.Context, d time.Duration) error
+}
+
+func newTokenBucket(requestsPerMinute int) *tokenBucket {
+	refillPerSecond := float64(requestsPerMinute) / 60
+	capacity := math.Max(1, math.Ceil(refillPerSecond))
+	return &tokenBucket{
+		capacity:        capacity,
+		tokens:          capacity,
+		refillPerSecond: refillPerSecond,
+		last:            time.Now(),
+		now:             time.Now,
+		sleep:           sleepWithContext,
+	}
+}
+
+// wait blocks until a token is available or the context is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		wait := b.take()
+		if wait == 0 {
+			return nil
+		}
+		if err := b.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// take removes a token from the bucket, if there are none it returns how long until one is available
+func (b *tokenBucket) take() time.Duration {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	now := b.now()
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.refillPerSecond)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.refillPerSecond * float64(time.Second))
+}
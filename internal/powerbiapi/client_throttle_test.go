@@ -0,0 +1,89 @@
+package powerbiapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_limitsSustainedRate(t *testing.T) {
+	now := time.Unix(0, 0)
+	var slept time.Duration
+
+	bucket := newTokenBucket(120)
+	bucket.last = now
+	bucket.now = func() time.Time { return now }
+	bucket.sleep = func(ctx context.Context, d time.Duration) error {
+		slept += d
+		now = now.Add(d)
+		return nil
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := bucket.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a burst of 2 is allowed, the remaining 8 requests are spread out at 2 per second
+	if slept != 4*time.Second {
+		t.Fatalf("expected to wait 4s but waited %v", slept)
+	}
+}
+
+func TestTokenBucket_stopsWaitingWhenContextIsDone(t *testing.T) {
+	bucket := newTokenBucket(1)
+	bucket.take()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := bucket.wait(ctx); err != context.Canceled {
+		t.Fatalf("expected context cancelled error but got %v", err)
+	}
+}
+
+func TestThrottleRoundTripper_limitsConcurrentRequests(t *testing.T) {
+	var mux sync.Mutex
+	inFlight, maxInFlight := 0, 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mux.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mux.Lock()
+		inFlight--
+		mux.Unlock()
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newThrottleRoundTripper(ThrottleOptions{MaxConcurrentRequests: 2}, http.DefaultTransport)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Fatalf("expected at most 2 concurrent requests but got %d", maxInFlight)
+	}
+}
@@ -0,0 +1,104 @@
+package powerbiapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+// DefaultTLSHandshakeTimeout is used when TransportOptions does not set a TLS handshake timeout. Power BI has
+// lots of intermittent TLS handshake issues, a long timeout seems to reduce the amount of issues encountered
+const DefaultTLSHandshakeTimeout = 60 * time.Second
+
+// TransportOptions represents how connections are made to Power BI and the login endpoint. The zero value
+// uses the proxy from the environment and the system root certificates
+type TransportOptions struct {
+	// ProxyURL is the proxy requests are sent through. The HTTPS_PROXY and NO_PROXY environment variables are used when empty.
+	// Requests to loopback and link-local hosts, such as the Azure Instance Metadata Service, are never proxied
+	ProxyURL string
+	// CACertFile is a PEM file of root certificates trusted in addition to the system root certificates
+	CACertFile string
+	// InsecureSkipVerify disables verifying server certificates. It must only be used with test stand-ins
+	InsecureSkipVerify bool
+	// TLSHandshakeTimeout is how long to wait for a TLS handshake. DefaultTLSHandshakeTimeout is used when zero
+	TLSHandshakeTimeout time.Duration
+}
+
+// newTransport creates a transport for the options. Pooled transports reuse connections across requests
+func newTransport(options TransportOptions, pooled bool) (*http.Transport, error) {
+	transport := cleanhttp.DefaultTransport()
+	if pooled {
+		transport = cleanhttp.DefaultPooledTransport()
+	}
+
+	transport.TLSHandshakeTimeout = DefaultTLSHandshakeTimeout
+	if options.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = options.TLSHandshakeTimeout
+	}
+
+	if options.ProxyURL != "" {
+		proxyURL, err := url.Parse(options.ProxyURL)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("Proxy URL '%s' must be an absolute URL such as http://proxy.example.com:8080", options.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	transport.Proxy = bypassProxyForLocalHosts(transport.Proxy)
+
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: options.InsecureSkipVerify,
+	}
+
+	if options.CACertFile != "" {
+		rootCAs, err := loadRootCAs(options.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig.RootCAs = rootCAs
+	}
+
+	return transport, nil
+}
+
+// bypassProxyForLocalHosts sends requests to loopback and link-local hosts directly. Managed identity
+// endpoints are only reachable from the machine itself so cannot be reached through a proxy
+func bypassProxyForLocalHosts(proxy func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	if proxy == nil {
+		return nil
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		host := req.URL.Hostname()
+		if host == "localhost" {
+			return nil, nil
+		}
+		if ip := net.ParseIP(host); ip != nil && (ip.IsLoopback() || ip.IsLinkLocalUnicast()) {
+			return nil, nil
+		}
+		return proxy(req)
+	}
+}
+
+// loadRootCAs returns the system root certificates with the certificates in the PEM file added
+func loadRootCAs(path string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read CA certificate file: %v", err)
+	}
+
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("CA certificate file %s does not contain any PEM encoded certificates", path)
+	}
+	return rootCAs, nil
+}
@@ -0,0 +1,164 @@
+package powerbiapi
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newTLSTestServer(t *testing.T) (*httptest.Server, string) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token") {
+			w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(`{"value":[]}`))
+	}))
+
+	file, err := ioutil.TempFile("", "ca-*.pem")
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	defer file.Close()
+	pem.Encode(file, &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	return server, file.Name()
+}
+
+func TestNewClient_trustsCACertFileForTokenAndAPIRequests(t *testing.T) {
+	server, caCertFile := newTLSTestServer(t)
+	defer server.Close()
+	defer os.Remove(caCertFile)
+
+	clientWithTransport := func(transport TransportOptions) *Client {
+		return newClientCredentialTestClient(t, server.URL, func(options *ClientOptions) {
+			options.Transport = transport
+		})
+	}
+
+	if _, err := clientWithTransport(TransportOptions{}).GetGateways(); err == nil {
+		t.Fatal("expected the test server certificate not to be trusted by default")
+	}
+
+	if _, err := clientWithTransport(TransportOptions{CACertFile: caCertFile}).GetGateways(); err != nil {
+		t.Fatalf("expected the test server certificate to be trusted but got %v", err)
+	}
+
+	if _, err := clientWithTransport(TransportOptions{InsecureSkipVerify: true}).GetGateways(); err != nil {
+		t.Fatalf("expected certificate verification to be skipped but got %v", err)
+	}
+}
+
+func TestNewClient_sendsTokenAndAPIRequestsThroughProxy(t *testing.T) {
+	var mux sync.Mutex
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.Lock()
+		proxied = append(proxied, r.URL.Path)
+		mux.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token") {
+			w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(`{"value":[]}`))
+	}))
+	defer proxy.Close()
+
+	// the target does not exist, requests only succeed if they are sent to the proxy
+	client := newClientCredentialTestClient(t, "http://powerbi.invalid", func(options *ClientOptions) {
+		options.Transport = TransportOptions{ProxyURL: proxy.URL}
+	})
+	if _, err := client.GetGateways(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "/" + testTenantID + "/oauth2/v2.0/token,/v1.0/myorg/gateways"
+	if strings.Join(proxied, ",") != expected {
+		t.Fatalf("expected requests %s to be proxied but got %s", expected, strings.Join(proxied, ","))
+	}
+}
+
+func TestNewTransport_doesNotProxyLocalHosts(t *testing.T) {
+	transport, err := newTransport(TransportOptions{ProxyURL: "http://proxy.example.com:8080"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range []string{
+		DefaultMSIEndpoint,
+		"http://[fe80::1]/metadata/identity/oauth2/token",
+		"http://localhost:8081/msi/token",
+		"http://127.0.0.1:41741/msi/token",
+	} {
+		req, _ := http.NewRequest("GET", target, nil)
+		proxyURL, err := transport.Proxy(req)
+		if err != nil || proxyURL != nil {
+			t.Fatalf("expected %s not to be proxied but got %v, %v", target, proxyURL, err)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "https://api.powerbi.com/v1.0/myorg/groups", nil)
+	proxyURL, err := transport.Proxy(req)
+	if err != nil || proxyURL == nil || proxyURL.Host != "proxy.example.com:8080" {
+		t.Fatalf("expected Power BI requests to be proxied but got %v, %v", proxyURL, err)
+	}
+}
+
+func TestNewClientWithManagedIdentityAuth_doesNotProxyTokenRequests(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected %s not to be proxied", r.URL)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+
+	msi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"access_token":"msi-token","expires_on":"4102444800"}`))
+	}))
+	defer msi.Close()
+
+	api := newBearerTestAPI("msi-token")
+	defer api.Close()
+
+	client, err := NewClientWithManagedIdentityAuth(ClientOptions{
+		Endpoints: Endpoints{APIURL: api.URL, ResourceURL: "https://analysis.windows.net/powerbi/api"},
+		Transport: TransportOptions{ProxyURL: proxy.URL},
+	}, ManagedIdentityOptions{
+		Endpoint: msi.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.RefreshUserPermissions(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClient_rejectsInvalidTransportOptions(t *testing.T) {
+	file, err := ioutil.TempFile("", "ca-*.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("not a certificate")
+	file.Close()
+	defer os.Remove(file.Name())
+
+	for _, options := range []TransportOptions{
+		{ProxyURL: "proxy.example.com"},
+		{CACertFile: file.Name()},
+		{CACertFile: file.Name() + ".missing"},
+	} {
+		_, err := NewClientWithAccessToken(ClientOptions{Endpoints: Endpoints{APIURL: "https://api.powerbi.com"}, Transport: options}, "token")
+		if err == nil {
+			t.Fatalf("expected options %+v to be rejected", options)
+		}
+	}
+}
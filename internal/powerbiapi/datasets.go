@@ -1,7 +1,7 @@
 package powerbiapi
 
 import (
-	"fmt"
+	"context"
 	"net/url"
 )
 
@@ -129,86 +129,131 @@ type UpdateRefreshScheduleInGroupRequestValue struct {
 
 // GetDatasetInGroup returns a dataset within the specified group.
 func (client *Client) GetDatasetInGroup(groupID string, datasetID string) (*GetDatasetInGroupResponse, error) {
+	return client.GetDatasetInGroupWithContext(context.Background(), groupID, datasetID)
+}
+
+// GetDatasetInGroupWithContext is the same as GetDatasetInGroup with the addition of a context to cancel the request
+func (client *Client) GetDatasetInGroupWithContext(ctx context.Context, groupID string, datasetID string) (*GetDatasetInGroupResponse, error) {
 
 	var respObj GetDatasetInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s", url.PathEscape(groupID), url.PathEscape(datasetID))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.groupURL(groupID, "/datasets/%s", url.PathEscape(datasetID))
+	err := client.doJSON(ctx, "GET", url, nil, &respObj)
 
 	return &respObj, err
 }
 
 // GetDatasetsInGroup returns a list of datasets within the specified group.
 func (client *Client) GetDatasetsInGroup(groupID string) (*GetDatasetsInGroupResponse, error) {
+	return client.GetDatasetsInGroupWithContext(context.Background(), groupID)
+}
+
+// GetDatasetsInGroupWithContext is the same as GetDatasetsInGroup with the addition of a context to cancel the request
+func (client *Client) GetDatasetsInGroupWithContext(ctx context.Context, groupID string) (*GetDatasetsInGroupResponse, error) {
 
 	var respObj GetDatasetsInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets", url.PathEscape(groupID))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.groupURL(groupID, "/datasets")
+	err := client.getAllPages(ctx, url, 0, &respObj.Value)
 
 	return &respObj, err
 }
 
 // DeleteDatasetInGroup deletes a dataset that exists within a group.
 func (client *Client) DeleteDatasetInGroup(groupID string, datasetID string) error {
+	return client.DeleteDatasetInGroupWithContext(context.Background(), groupID, datasetID)
+}
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s", url.PathEscape(groupID), url.PathEscape(datasetID))
-	err := client.doJSON("DELETE", url, nil, nil)
+// DeleteDatasetInGroupWithContext is the same as DeleteDatasetInGroup with the addition of a context to cancel the request
+func (client *Client) DeleteDatasetInGroupWithContext(ctx context.Context, groupID string, datasetID string) error {
+
+	url := client.groupURL(groupID, "/datasets/%s", url.PathEscape(datasetID))
+	err := client.doJSON(ctx, "DELETE", url, nil, nil)
 
 	return err
 }
 
 // GetParametersInGroup gets parameters in a dataset that exists within a group.
 func (client *Client) GetParametersInGroup(groupID string, datasetID string) (*GetParametersInGroupResponse, error) {
+	return client.GetParametersInGroupWithContext(context.Background(), groupID, datasetID)
+}
+
+// GetParametersInGroupWithContext is the same as GetParametersInGroup with the addition of a context to cancel the request
+func (client *Client) GetParametersInGroupWithContext(ctx context.Context, groupID string, datasetID string) (*GetParametersInGroupResponse, error) {
 
 	var respObj GetParametersInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/parameters", url.PathEscape(groupID), url.PathEscape(datasetID))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.groupURL(groupID, "/datasets/%s/parameters", url.PathEscape(datasetID))
+	err := client.doJSON(ctx, "GET", url, nil, &respObj)
 
 	return &respObj, err
 }
 
 // UpdateParametersInGroup updates parameters in a dataset that exists within a group.
 func (client *Client) UpdateParametersInGroup(groupID string, datasetID string, request UpdateParametersInGroupRequest) error {
+	return client.UpdateParametersInGroupWithContext(context.Background(), groupID, datasetID, request)
+}
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/Default.UpdateParameters", url.PathEscape(groupID), url.PathEscape(datasetID))
-	err := client.doJSON("POST", url, &request, nil)
+// UpdateParametersInGroupWithContext is the same as UpdateParametersInGroup with the addition of a context to cancel the request
+func (client *Client) UpdateParametersInGroupWithContext(ctx context.Context, groupID string, datasetID string, request UpdateParametersInGroupRequest) error {
+
+	url := client.groupURL(groupID, "/datasets/%s/Default.UpdateParameters", url.PathEscape(datasetID))
+	err := client.doJSON(ctx, "POST", url, &request, nil)
 
 	return err
 }
 
 // GetDatasourcesInGroup gets datasources in a dataset that exists within a group.
 func (client *Client) GetDatasourcesInGroup(groupID string, datasetID string) (*GetDatasourcesInGroupResponse, error) {
+	return client.GetDatasourcesInGroupWithContext(context.Background(), groupID, datasetID)
+}
+
+// GetDatasourcesInGroupWithContext is the same as GetDatasourcesInGroup with the addition of a context to cancel the request
+func (client *Client) GetDatasourcesInGroupWithContext(ctx context.Context, groupID string, datasetID string) (*GetDatasourcesInGroupResponse, error) {
 
 	var respObj GetDatasourcesInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/datasources", url.PathEscape(groupID), url.PathEscape(datasetID))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.groupURL(groupID, "/datasets/%s/datasources", url.PathEscape(datasetID))
+	err := client.doJSON(ctx, "GET", url, nil, &respObj)
 
 	return &respObj, err
 }
 
 // UpdateDatasourcesInGroup updates datasources in a dataset that exists within a group.
 func (client *Client) UpdateDatasourcesInGroup(groupID string, datasetID string, request UpdateDatasourcesInGroupRequest) error {
+	return client.UpdateDatasourcesInGroupWithContext(context.Background(), groupID, datasetID, request)
+}
+
+// UpdateDatasourcesInGroupWithContext is the same as UpdateDatasourcesInGroup with the addition of a context to cancel the request
+func (client *Client) UpdateDatasourcesInGroupWithContext(ctx context.Context, groupID string, datasetID string, request UpdateDatasourcesInGroupRequest) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/Default.UpdateDatasources", url.PathEscape(groupID), url.PathEscape(datasetID))
-	err := client.doJSON("POST", url, &request, nil)
+	url := client.groupURL(groupID, "/datasets/%s/Default.UpdateDatasources", url.PathEscape(datasetID))
+	err := client.doJSON(ctx, "POST", url, &request, nil)
 
 	return err
 }
 
 // GetRefreshScheduleInGroup gets a datasource's refresh schedule.
 func (client *Client) GetRefreshScheduleInGroup(groupID string, datasetID string) (*GetRefreshScheduleInGroupResponse, error) {
+	return client.GetRefreshScheduleInGroupWithContext(context.Background(), groupID, datasetID)
+}
+
+// GetRefreshScheduleInGroupWithContext is the same as GetRefreshScheduleInGroup with the addition of a context to cancel the request
+func (client *Client) GetRefreshScheduleInGroupWithContext(ctx context.Context, groupID string, datasetID string) (*GetRefreshScheduleInGroupResponse, error) {
 
 	var respObj GetRefreshScheduleInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/refreshSchedule", url.PathEscape(groupID), url.PathEscape(datasetID))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.groupURL(groupID, "/datasets/%s/refreshSchedule", url.PathEscape(datasetID))
+	err := client.doJSON(ctx, "GET", url, nil, &respObj)
 
 	return &respObj, err
 }
 
 // UpdateRefreshScheduleInGroup updates a datasource's refresh schedule.
 func (client *Client) UpdateRefreshScheduleInGroup(groupID string, datasetID string, request UpdateRefreshScheduleInGroupRequest) error {
+	return client.UpdateRefreshScheduleInGroupWithContext(context.Background(), groupID, datasetID, request)
+}
+
+// UpdateRefreshScheduleInGroupWithContext is the same as UpdateRefreshScheduleInGroup with the addition of a context to cancel the request
+func (client *Client) UpdateRefreshScheduleInGroupWithContext(ctx context.Context, groupID string, datasetID string, request UpdateRefreshScheduleInGroupRequest) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/refreshSchedule", url.PathEscape(groupID), url.PathEscape(datasetID))
-	err := client.doJSON("PATCH", url, &request, nil)
+	url := client.groupURL(groupID, "/datasets/%s/refreshSchedule", url.PathEscape(datasetID))
+	err := client.doJSON(ctx, "PATCH", url, &request, nil)
 
 	return err
 }
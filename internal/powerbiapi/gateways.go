@@ -1,7 +1,7 @@
 package powerbiapi
 
 import (
-	"fmt"
+	"context"
 	"net/url"
 )
 
@@ -119,91 +119,141 @@ type GetDatasourceUsersResponseItem struct {
 
 // CreateDatasource creates new datasource
 func (client *Client) CreateDatasource(gatewayId string, request CreateDatasourceRequest) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/gateways/%s/datasources", url.PathEscape(gatewayId))
-	err := client.doJSON("POST", url, &request, nil)
+	return client.CreateDatasourceWithContext(context.Background(), gatewayId, request)
+}
+
+// CreateDatasourceWithContext is the same as CreateDatasource with the addition of a context to cancel the request
+func (client *Client) CreateDatasourceWithContext(ctx context.Context, gatewayId string, request CreateDatasourceRequest) error {
+	url := client.url("/gateways/%s/datasources", url.PathEscape(gatewayId))
+	err := client.doJSON(ctx, "POST", url, &request, nil)
 	return err
 }
 
 // Grants or updates the permissions required to use the specified data source.
 func (client *Client) DeleteDatasource(gatewayId string, datasourceId string) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/gateways/%s/datasources/%s", url.PathEscape(gatewayId), url.PathEscape(datasourceId))
-	err := client.doJSON("DELETE", url, nil, nil)
+	return client.DeleteDatasourceWithContext(context.Background(), gatewayId, datasourceId)
+}
+
+// DeleteDatasourceWithContext is the same as DeleteDatasource with the addition of a context to cancel the request
+func (client *Client) DeleteDatasourceWithContext(ctx context.Context, gatewayId string, datasourceId string) error {
+	url := client.url("/gateways/%s/datasources/%s", url.PathEscape(gatewayId), url.PathEscape(datasourceId))
+	err := client.doJSON(ctx, "DELETE", url, nil, nil)
 
 	return err
 }
 
 // Grants or updates the permissions required to use the specified data source for the specified user.
 func (client *Client) DeleteDatasourceUser(gatewayId string, datasourceId string, emailAdress string) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/gateways/%s/datasources/%s/users/%s", url.PathEscape(gatewayId), url.PathEscape(datasourceId), url.PathEscape(emailAdress))
-	err := client.doJSON("DELETE", url, nil, nil)
+	return client.DeleteDatasourceUserWithContext(context.Background(), gatewayId, datasourceId, emailAdress)
+}
+
+// DeleteDatasourceUserWithContext is the same as DeleteDatasourceUser with the addition of a context to cancel the request
+func (client *Client) DeleteDatasourceUserWithContext(ctx context.Context, gatewayId string, datasourceId string, emailAdress string) error {
+	url := client.url("/gateways/%s/datasources/%s/users/%s", url.PathEscape(gatewayId), url.PathEscape(datasourceId), url.PathEscape(emailAdress))
+	err := client.doJSON(ctx, "DELETE", url, nil, nil)
 
 	return err
 }
 
 // Removes the specified user from the specified data source.
 func (client *Client) AddDatasourceUser(gatewayId string, datasourceId string, request AddDatasouceUserRequest) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/gateways/%s/datasources/%s/users", url.PathEscape(gatewayId), url.PathEscape(datasourceId))
-	err := client.doJSON("POST", url, &request, nil)
+	return client.AddDatasourceUserWithContext(context.Background(), gatewayId, datasourceId, request)
+}
+
+// AddDatasourceUserWithContext is the same as AddDatasourceUser with the addition of a context to cancel the request
+func (client *Client) AddDatasourceUserWithContext(ctx context.Context, gatewayId string, datasourceId string, request AddDatasouceUserRequest) error {
+	url := client.url("/gateways/%s/datasources/%s/users", url.PathEscape(gatewayId), url.PathEscape(datasourceId))
+	err := client.doJSON(ctx, "POST", url, &request, nil)
 
 	return err
 }
 
 // Returns a list of gateways for which the user is an admin.
 func (client *Client) GetGateways() (*GetGatewaysResponse, error) {
+	return client.GetGatewaysWithContext(context.Background())
+}
+
+// GetGatewaysWithContext is the same as GetGateways with the addition of a context to cancel the request
+func (client *Client) GetGatewaysWithContext(ctx context.Context) (*GetGatewaysResponse, error) {
 
 	var respObj GetGatewaysResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/gateways")
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.url("/gateways")
+	err := client.getAllPages(ctx, url, 0, &respObj.Value)
 
 	return &respObj, err
 }
 
 // Returns the specified gateway.
 func (client *Client) GetGateway(gatewayId string) (*GetGatewaysResponseItem, error) {
+	return client.GetGatewayWithContext(context.Background(), gatewayId)
+}
+
+// GetGatewayWithContext is the same as GetGateway with the addition of a context to cancel the request
+func (client *Client) GetGatewayWithContext(ctx context.Context, gatewayId string) (*GetGatewaysResponseItem, error) {
 
 	var respObj GetGatewaysResponseItem
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/gateways/%s", url.PathEscape(gatewayId))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.url("/gateways/%s", url.PathEscape(gatewayId))
+	err := client.doJSON(ctx, "GET", url, nil, &respObj)
 
 	return &respObj, err
 }
 
 // Returns a list of data sources from the specified gateway.
 func (client *Client) GetDatasources(gatewayId string) (*GetDatasourcesResponse, error) {
+	return client.GetDatasourcesWithContext(context.Background(), gatewayId)
+}
+
+// GetDatasourcesWithContext is the same as GetDatasources with the addition of a context to cancel the request
+func (client *Client) GetDatasourcesWithContext(ctx context.Context, gatewayId string) (*GetDatasourcesResponse, error) {
 
 	var respObj GetDatasourcesResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/gateways/%s/datasources", url.PathEscape(gatewayId))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.url("/gateways/%s/datasources", url.PathEscape(gatewayId))
+	err := client.doJSON(ctx, "GET", url, nil, &respObj)
 
 	return &respObj, err
 }
 
 // Returns the specified data source from the specified gateway.
 func (client *Client) GetDatasource(gatewayId string, datasourceId string) (*GetDatasourcesResponseItem, error) {
+	return client.GetDatasourceWithContext(context.Background(), gatewayId, datasourceId)
+}
+
+// GetDatasourceWithContext is the same as GetDatasource with the addition of a context to cancel the request
+func (client *Client) GetDatasourceWithContext(ctx context.Context, gatewayId string, datasourceId string) (*GetDatasourcesResponseItem, error) {
 
 	var respObj GetDatasourcesResponseItem
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/gateways/%s/datasources/%s", url.PathEscape(gatewayId), url.PathEscape(datasourceId))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.url("/gateways/%s/datasources/%s", url.PathEscape(gatewayId), url.PathEscape(datasourceId))
+	err := client.doJSON(ctx, "GET", url, nil, &respObj)
 
 	return &respObj, err
 }
 
 // Checks the connectivity status of the specified data source from the specified gateway.
 func (client *Client) GetDatasourceStatus(gatewayId string, datasourceId string) (*GetDatasourceStatusResponse, error) {
+	return client.GetDatasourceStatusWithContext(context.Background(), gatewayId, datasourceId)
+}
+
+// GetDatasourceStatusWithContext is the same as GetDatasourceStatus with the addition of a context to cancel the request
+func (client *Client) GetDatasourceStatusWithContext(ctx context.Context, gatewayId string, datasourceId string) (*GetDatasourceStatusResponse, error) {
 
 	var respObj GetDatasourceStatusResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/gateways/%s/datasources/%s/status", url.PathEscape(gatewayId), url.PathEscape(datasourceId))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.url("/gateways/%s/datasources/%s/status", url.PathEscape(gatewayId), url.PathEscape(datasourceId))
+	err := client.doJSON(ctx, "GET", url, nil, &respObj)
 
 	return &respObj, err
 }
 
 // Returns a list of users who have access to the specified data source.
 func (client *Client) GetDatasourceUsers(gatewayId string, datasourceId string) (*GetDatasourceUsersResponse, error) {
+	return client.GetDatasourceUsersWithContext(context.Background(), gatewayId, datasourceId)
+}
+
+// GetDatasourceUsersWithContext is the same as GetDatasourceUsers with the addition of a context to cancel the request
+func (client *Client) GetDatasourceUsersWithContext(ctx context.Context, gatewayId string, datasourceId string) (*GetDatasourceUsersResponse, error) {
 
 	var respObj GetDatasourceUsersResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/gateways/%s/datasources/%s/users", url.PathEscape(gatewayId), url.PathEscape(datasourceId))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.url("/gateways/%s/datasources/%s/users", url.PathEscape(gatewayId), url.PathEscape(datasourceId))
+	err := client.doJSON(ctx, "GET", url, nil, &respObj)
 
 	return &respObj, err
 }
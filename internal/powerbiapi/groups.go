@@ -1,9 +1,10 @@
 package powerbiapi
 
 import (
+	"context"
 	"fmt"
 	"net/url"
-	"strconv"
+	"strings"
 )
 
 // CreateGroupRequest represents the request for the CreateGroup API
@@ -44,6 +45,11 @@ type GetGroupResponse struct {
 	dataflowStorageId     string
 }
 
+// UpdateGroupRequest represents the request for the UpdateGroup API. Descriptions can only be updated with UpdateGroupAsAdmin
+type UpdateGroupRequest struct {
+	Name string `json:"name,omitempty"`
+}
+
 // GetGroupUsersResponse represents list of users that have access to the specified workspace.
 type GetGroupUsersResponse struct {
 	Value []GetGroupUsersResponseItem
@@ -78,38 +84,67 @@ type UpdateGroupUserRequest struct {
 
 // CreateGroup creates new workspace
 func (client *Client) CreateGroup(request CreateGroupRequest) (*CreateGroupResponse, error) {
+	return client.CreateGroupWithContext(context.Background(), request)
+}
+
+// CreateGroupWithContext is the same as CreateGroup with the addition of a context to cancel the request
+func (client *Client) CreateGroupWithContext(ctx context.Context, request CreateGroupRequest) (*CreateGroupResponse, error) {
 
 	var respObj CreateGroupResponse
-	err := client.doJSON("POST", "https://api.powerbi.com/v1.0/myorg/groups?workspaceV2=True", request, &respObj)
+	err := client.doJSON(ctx, "POST", client.url("/groups?workspaceV2=True"), request, &respObj)
+	client.cache.invalidate(groupsCacheKey)
 	return &respObj, err
 }
 
-// GetGroups returns a list of workspaces the user has access to.
+// GetGroups returns a list of workspaces the user has access to. All pages are read when top is not greater than zero
 func (client *Client) GetGroups(filter string, top int, skip int) (*GetGroupsResponse, error) {
+	return client.GetGroupsWithContext(context.Background(), filter, top, skip)
+}
 
+// GetGroupsWithContext is the same as GetGroups with the addition of a context to cancel the request
+func (client *Client) GetGroupsWithContext(ctx context.Context, filter string, top int, skip int) (*GetGroupsResponse, error) {
+
+	var respObj GetGroupsResponse
+	err := readAllPages(client.newGroupsIterator(ctx, filter, top, skip), &respObj.Value)
+
+	return &respObj, err
+}
+
+// GetGroupsIterator streams the workspaces the user has access to, requesting pages as they are read.
+// Items decode to GetGroupsResponseItem
+func (client *Client) GetGroupsIterator(filter string) *PageIterator {
+	return client.GetGroupsIteratorWithContext(context.Background(), filter)
+}
+
+// GetGroupsIteratorWithContext is the same as GetGroupsIterator with the addition of a context to cancel the requests
+func (client *Client) GetGroupsIteratorWithContext(ctx context.Context, filter string) *PageIterator {
+	return client.newGroupsIterator(ctx, filter, 0, 0)
+}
+
+func (client *Client) newGroupsIterator(ctx context.Context, filter string, top int, skip int) *PageIterator {
 	queryParams := url.Values{}
 	if filter != "" {
 		queryParams.Add("$filter", filter)
 	}
-	if top > 0 {
-		queryParams.Add("$top", strconv.Itoa(top))
-	}
-	if skip > 0 {
-		queryParams.Add("$skip", strconv.Itoa(skip))
-	}
 
-	var respObj GetGroupsResponse
-	err := client.doJSON("GET", "https://api.powerbi.com/v1.0/myorg/groups?"+queryParams.Encode(), nil, &respObj)
-
-	return &respObj, err
+	return client.newPageIterator(ctx, client.url("/groups?%s", queryParams.Encode()), defaultPageSize, skip, top)
 }
 
 // GetGroup returns a single workspace
 func (client *Client) GetGroup(groupID string) (*GetGroupResponse, error) {
+	return client.GetGroupWithContext(context.Background(), groupID)
+}
+
+// GetGroupWithContext is the same as GetGroup with the addition of a context to cancel the request
+func (client *Client) GetGroupWithContext(ctx context.Context, groupID string) (*GetGroupResponse, error) {
+
+	if client.cache != nil {
+		return client.getCachedGroup(ctx, groupID)
+	}
 
 	// There is no endpoint to get a single workspace, so we will search for
 	// all workspaces with a specific id
-	groups, err := client.GetGroups(fmt.Sprintf("id eq '%s'", groupID), -1, 0)
+	groups, err := client.GetGroupsWithContext(ctx, fmt.Sprintf("id eq '%s'", groupID), -1, 0)
 
 	if err != nil {
 		return nil, err
@@ -119,7 +154,29 @@ func (client *Client) GetGroup(groupID string) (*GetGroupResponse, error) {
 		return nil, nil
 	}
 
-	singleGroup := &groups.Value[0]
+	return newGetGroupResponse(&groups.Value[0]), nil
+}
+
+// getCachedGroup finds the workspace within the cached list of every workspace, so reading many workspaces
+// only lists them once rather than searching for each
+func (client *Client) getCachedGroup(ctx context.Context, groupID string) (*GetGroupResponse, error) {
+	groups, err := client.cache.get(ctx, client.profileCacheKey(ctx, groupsCacheKey), func() (interface{}, error) {
+		groups, err := client.GetGroupsWithContext(ctx, "", 0, 0)
+		return groups.Value, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups.([]GetGroupsResponseItem) {
+		if strings.EqualFold(group.ID, groupID) {
+			return newGetGroupResponse(&group), nil
+		}
+	}
+	return nil, nil
+}
+
+func newGetGroupResponse(singleGroup *GetGroupsResponseItem) *GetGroupResponse {
 	return &GetGroupResponse{
 		ID:                    singleGroup.ID,
 		IsOnDedicatedCapacity: singleGroup.IsOnDedicatedCapacity,
@@ -127,15 +184,20 @@ func (client *Client) GetGroup(groupID string) (*GetGroupResponse, error) {
 		CapacityID:            singleGroup.CapacityID,
 		IsReadOnly:            singleGroup.IsReadOnly,
 		dataflowStorageId:     singleGroup.dataflowStorageId,
-	}, nil
+	}
 }
 
 // GetGroupByName returns a single workspace
 func (client *Client) GetGroupByName(groupName string) (*GetGroupResponse, error) {
+	return client.GetGroupByNameWithContext(context.Background(), groupName)
+}
+
+// GetGroupByNameWithContext is the same as GetGroupByName with the addition of a context to cancel the request
+func (client *Client) GetGroupByNameWithContext(ctx context.Context, groupName string) (*GetGroupResponse, error) {
 
 	// There is no endpoint to get a single workspace, so we will search for
 	// all workspaces with a specific name
-	groups, err := client.GetGroups(fmt.Sprintf("name eq '%s'", groupName), -1, 0)
+	groups, err := client.GetGroupsWithContext(ctx, fmt.Sprintf("name eq '%s'", groupName), -1, 0)
 
 	if err != nil {
 		return nil, err
@@ -145,53 +207,98 @@ func (client *Client) GetGroupByName(groupName string) (*GetGroupResponse, error
 		return nil, nil
 	}
 
-	singleGroup := &groups.Value[0]
-	return &GetGroupResponse{
-		ID:                    singleGroup.ID,
-		IsOnDedicatedCapacity: singleGroup.IsOnDedicatedCapacity,
-		Name:                  singleGroup.Name,
-		CapacityID:            singleGroup.CapacityID,
-		IsReadOnly:            singleGroup.IsReadOnly,
-		dataflowStorageId:     singleGroup.dataflowStorageId,
-	}, nil
+	return newGetGroupResponse(&groups.Value[0]), nil
+}
+
+// UpdateGroup updates the name of a workspace
+func (client *Client) UpdateGroup(groupID string, request UpdateGroupRequest) error {
+	return client.UpdateGroupWithContext(context.Background(), groupID, request)
+}
+
+// UpdateGroupWithContext is the same as UpdateGroup with the addition of a context to cancel the request
+func (client *Client) UpdateGroupWithContext(ctx context.Context, groupID string, request UpdateGroupRequest) error {
+	url := client.url("/groups/%s", url.PathEscape(groupID))
+	err := client.doJSON(ctx, "PATCH", url, &request, nil)
+	client.cache.invalidate(groupsCacheKey)
+
+	return err
 }
 
 // DeleteGroup deletes a workspace
 func (client *Client) DeleteGroup(groupID string) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s", url.PathEscape(groupID))
-	return client.doJSON("DELETE", url, nil, nil)
+	return client.DeleteGroupWithContext(context.Background(), groupID)
+}
+
+// DeleteGroupWithContext is the same as DeleteGroup with the addition of a context to cancel the request
+func (client *Client) DeleteGroupWithContext(ctx context.Context, groupID string) error {
+	url := client.url("/groups/%s", url.PathEscape(groupID))
+	err := client.doJSON(ctx, "DELETE", url, nil, nil)
+	client.cache.invalidate(groupsCacheKey)
+
+	return err
 }
 
 // GetGroupUsers Returns a list of users that have access to the specified workspace.
 func (client *Client) GetGroupUsers(groupID string) (*GetGroupUsersResponse, error) {
+	return client.GetGroupUsersWithContext(context.Background(), groupID)
+}
+
+// GetGroupUsersWithContext is the same as GetGroupUsers with the addition of a context to cancel the request
+func (client *Client) GetGroupUsersWithContext(ctx context.Context, groupID string) (*GetGroupUsersResponse, error) {
 
 	var respObj GetGroupUsersResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/users", url.PathEscape(groupID))
-	err := client.doJSON("GET", url, nil, &respObj)
+	err := readAllPages(client.GetGroupUsersIteratorWithContext(ctx, groupID), &respObj.Value)
 
 	return &respObj, err
 }
 
+// GetGroupUsersIterator streams the users that have access to the specified workspace, requesting pages as they are read.
+// Items decode to GetGroupUsersResponseItem
+func (client *Client) GetGroupUsersIterator(groupID string) *PageIterator {
+	return client.GetGroupUsersIteratorWithContext(context.Background(), groupID)
+}
+
+// GetGroupUsersIteratorWithContext is the same as GetGroupUsersIterator with the addition of a context to cancel the requests
+func (client *Client) GetGroupUsersIteratorWithContext(ctx context.Context, groupID string) *PageIterator {
+	url := client.url("/groups/%s/users", url.PathEscape(groupID))
+	return client.newPageIterator(ctx, url, defaultPageSize, 0, 0)
+}
+
 // AddGroupUser Grants the specified user permissions to the specified workspace.
 func (client *Client) AddGroupUser(groupID string, request AddGroupUserRequest) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/users", url.PathEscape(groupID))
-	err := client.doJSON("POST", url, &request, nil)
+	return client.AddGroupUserWithContext(context.Background(), groupID, request)
+}
+
+// AddGroupUserWithContext is the same as AddGroupUser with the addition of a context to cancel the request
+func (client *Client) AddGroupUserWithContext(ctx context.Context, groupID string, request AddGroupUserRequest) error {
+	url := client.url("/groups/%s/users", url.PathEscape(groupID))
+	err := client.doJSON(ctx, "POST", url, &request, nil)
 
 	return err
 }
 
 // UpdateGroupUser Update the specified user permissions to the specified workspace.
 func (client *Client) UpdateGroupUser(groupID string, request UpdateGroupUserRequest) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/users", url.PathEscape(groupID))
-	err := client.doJSON("PUT", url, &request, nil)
+	return client.UpdateGroupUserWithContext(context.Background(), groupID, request)
+}
+
+// UpdateGroupUserWithContext is the same as UpdateGroupUser with the addition of a context to cancel the request
+func (client *Client) UpdateGroupUserWithContext(ctx context.Context, groupID string, request UpdateGroupUserRequest) error {
+	url := client.url("/groups/%s/users", url.PathEscape(groupID))
+	err := client.doJSON(ctx, "PUT", url, &request, nil)
 
 	return err
 }
 
 // DeleteUserInGroup Deletes the specified user permissions from the specified workspace.
 func (client *Client) DeleteUserInGroup(groupID string, userInfo string) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/users/%s", url.PathEscape(groupID), url.PathEscape(userInfo))
-	err := client.doJSON("DELETE", url, nil, nil)
+	return client.DeleteUserInGroupWithContext(context.Background(), groupID, userInfo)
+}
+
+// DeleteUserInGroupWithContext is the same as DeleteUserInGroup with the addition of a context to cancel the request
+func (client *Client) DeleteUserInGroupWithContext(ctx context.Context, groupID string, userInfo string) error {
+	url := client.url("/groups/%s/users/%s", url.PathEscape(groupID), url.PathEscape(userInfo))
+	err := client.doJSON(ctx, "DELETE", url, nil, nil)
 
 	return err
 }
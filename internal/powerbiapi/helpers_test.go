@@ -0,0 +1,73 @@
+package powerbiapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+const (
+	testTenantID     = "my-tenant-id"
+	testClientID     = "my-client-id"
+	testClientSecret = "my-client-secret"
+)
+
+// newTestClient creates a client for a test server that authenticates with a fixed access token. Retries are
+// disabled so errors are returned straight away, configure can change any other options
+func newTestClient(t *testing.T, apiURL string, configure func(options *ClientOptions)) *Client {
+	options := ClientOptions{
+		Endpoints: Endpoints{APIURL: apiURL},
+		Retry:     &RetryOptions{},
+	}
+	if configure != nil {
+		configure(&options)
+	}
+
+	client, err := NewClientWithAccessToken(options, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// newClientCredentialTestClient creates a client that uses client credential authentication, with the test server
+// at url acting as both the API and the login endpoint
+func newClientCredentialTestClient(t *testing.T, url string, configure func(options *ClientOptions)) *Client {
+	options := ClientOptions{
+		Endpoints: Endpoints{APIURL: url, LoginURL: url, ResourceURL: "https://analysis.windows.net/powerbi/api"},
+		Retry:     &RetryOptions{},
+	}
+	if configure != nil {
+		configure(&options)
+	}
+
+	client, err := NewClientWithClientCredentialAuth(options, testTenantID, testClientID, testClientSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// newBearerTestAPI returns a server that only accepts requests with the expected bearer token
+func newBearerTestAPI(expectedToken string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+expectedToken {
+			w.WriteHeader(401)
+			return
+		}
+		w.WriteHeader(200)
+	}))
+}
+
+// newRecordingTestServer returns a server that responds with an empty list and records each request as described by describe
+func newRecordingTestServer(requests *[]string, describe func(r *http.Request) string) *httptest.Server {
+	var mux sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.Lock()
+		*requests = append(*requests, describe(r))
+		mux.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"value":[]}`))
+	}))
+}
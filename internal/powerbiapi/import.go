@@ -1,6 +1,10 @@
 package powerbiapi
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"net/url"
@@ -75,9 +79,177 @@ type GetImportsInGroupResponseItemReport struct {
 	WebURL     string
 }
 
-// PostImportInGroup creates an import within the the specified group
+// PostImportInGroup creates an import within the the specified group. The content is streamed rather than
+// buffered, requestData should implement io.Seeker (such as *os.File) for the upload to be retried on failure
 func (client *Client) PostImportInGroup(groupID string, datasetDisplayName string, nameConflict string, skipReport bool, requestData io.Reader) (*PostImportInGroupResponse, error) {
+	return client.PostImportInGroupWithContext(context.Background(), groupID, datasetDisplayName, nameConflict, skipReport, requestData)
+}
+
+// PostImportInGroupWithContext is the same as PostImportInGroup with the addition of a context to cancel the request
+func (client *Client) PostImportInGroupWithContext(ctx context.Context, groupID string, datasetDisplayName string, nameConflict string, skipReport bool, requestData io.Reader) (*PostImportInGroupResponse, error) {
+
+	var respObj PostImportInGroupResponse
+	url := client.groupURL(groupID, "/imports?%s", importQueryParams(datasetDisplayName, nameConflict, skipReport).Encode())
+	err := client.doMultipartJSON(ctx, "POST", url, requestData, &respObj)
+
+	return &respObj, err
+}
+
+// LargeImportThreshold is the file size above which imports must be uploaded through a temporary upload location
+const LargeImportThreshold int64 = 1 << 30
+
+// temporaryUploadBlockSize is the size of each block uploaded to a temporary upload location
+var temporaryUploadBlockSize = 32 << 20
+
+// CreateTemporaryUploadLocationResponse represents the response from creating a temporary upload location
+type CreateTemporaryUploadLocationResponse struct {
+	URL            string
+	ExpirationTime time.Time
+}
+
+// PostImportFromFileURLInGroupRequest represents the request to create an import from a file already uploaded to a URL
+type PostImportFromFileURLInGroupRequest struct {
+	FileURL string `json:"fileUrl"`
+}
+
+// CreateTemporaryUploadLocationInGroup creates a temporary blob storage upload location for imports of .pbix files larger than 1 GB
+func (client *Client) CreateTemporaryUploadLocationInGroup(groupID string) (*CreateTemporaryUploadLocationResponse, error) {
+	return client.CreateTemporaryUploadLocationInGroupWithContext(context.Background(), groupID)
+}
+
+// CreateTemporaryUploadLocationInGroupWithContext is the same as CreateTemporaryUploadLocationInGroup with the addition of a context to cancel the request
+func (client *Client) CreateTemporaryUploadLocationInGroupWithContext(ctx context.Context, groupID string) (*CreateTemporaryUploadLocationResponse, error) {
+
+	var respObj CreateTemporaryUploadLocationResponse
+	url := client.groupURL(groupID, "/imports/createTemporaryUploadLocation")
+	err := client.doJSON(ctx, "POST", url, nil, &respObj)
+
+	return &respObj, err
+}
+
+// UploadToTemporaryUploadLocation uploads the content to a temporary upload location as a block blob.
+// The content is sent in blocks so only a single block is held in memory, and each block can be retried independently
+func (client *Client) UploadToTemporaryUploadLocation(uploadURL string, content io.Reader) error {
+	return client.UploadToTemporaryUploadLocationWithContext(context.Background(), uploadURL, content)
+}
+
+// UploadToTemporaryUploadLocationWithContext is the same as UploadToTemporaryUploadLocation with the addition of a context to cancel the request
+func (client *Client) UploadToTemporaryUploadLocationWithContext(ctx context.Context, uploadURL string, content io.Reader) error {
+
+	var blockIDs []string
+	block := make([]byte, temporaryUploadBlockSize)
+	for {
+		n, readErr := io.ReadFull(content, block)
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			return readErr
+		}
+		if n == 0 {
+			break
+		}
+
+		// block IDs must all be the same length within a blob
+		blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("block-%08d", len(blockIDs))))
+		blockURL, err := blobURL(uploadURL, url.Values{"comp": {"block"}, "blockid": {blockID}})
+		if err != nil {
+			return err
+		}
+		if err := client.doBlob(ctx, blockURL, "application/octet-stream", block[:n]); err != nil {
+			return err
+		}
+		blockIDs = append(blockIDs, blockID)
+
+		if readErr != nil {
+			break
+		}
+	}
+
+	var blockList bytes.Buffer
+	blockList.WriteString(xml.Header)
+	blockList.WriteString("<BlockList>")
+	for _, blockID := range blockIDs {
+		blockList.WriteString("<Latest>")
+		xml.EscapeText(&blockList, []byte(blockID))
+		blockList.WriteString("</Latest>")
+	}
+	blockList.WriteString("</BlockList>")
+
+	blockListURL, err := blobURL(uploadURL, url.Values{"comp": {"blocklist"}})
+	if err != nil {
+		return err
+	}
+	return client.doBlob(ctx, blockListURL, "application/xml", blockList.Bytes())
+}
+
+// blobURL adds the blob operation parameters to the query of the upload URL, keeping its SAS token
+func blobURL(uploadURL string, values url.Values) (string, error) {
+	parsedURL, err := url.Parse(uploadURL)
+	if err != nil {
+		return "", err
+	}
+	query := parsedURL.Query()
+	for key := range values {
+		query.Set(key, values.Get(key))
+	}
+	parsedURL.RawQuery = query.Encode()
+	return parsedURL.String(), nil
+}
+
+func (client *Client) doBlob(ctx context.Context, blobURL string, contentType string, data []byte) error {
+	req, err := newReplayableRequest(ctx, "PUT", blobURL, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-ms-version", "2019-12-12")
+
+	resp, err := client.blobClient.Do(req)
+	if err != nil {
+		return err
+	}
+	closeResponse(resp)
+	return nil
+}
+
+// PostImportFromFileURLInGroup creates an import within the specified group from a file previously uploaded to a temporary upload location
+func (client *Client) PostImportFromFileURLInGroup(groupID string, datasetDisplayName string, nameConflict string, skipReport bool, fileURL string) (*PostImportInGroupResponse, error) {
+	return client.PostImportFromFileURLInGroupWithContext(context.Background(), groupID, datasetDisplayName, nameConflict, skipReport, fileURL)
+}
+
+// PostImportFromFileURLInGroupWithContext is the same as PostImportFromFileURLInGroup with the addition of a context to cancel the request
+func (client *Client) PostImportFromFileURLInGroupWithContext(ctx context.Context, groupID string, datasetDisplayName string, nameConflict string, skipReport bool, fileURL string) (*PostImportInGroupResponse, error) {
+
+	var respObj PostImportInGroupResponse
+	url := client.groupURL(groupID, "/imports?%s", importQueryParams(datasetDisplayName, nameConflict, skipReport).Encode())
+	err := client.doJSON(ctx, "POST", url, PostImportFromFileURLInGroupRequest{
+		FileURL: fileURL,
+	}, &respObj)
+
+	return &respObj, err
+}
+
+// PostLargeImportInGroup creates an import within the specified group by uploading the content to a temporary upload location.
+// This is required for .pbix files larger than LargeImportThreshold
+func (client *Client) PostLargeImportInGroup(groupID string, datasetDisplayName string, nameConflict string, skipReport bool, requestData io.Reader) (*PostImportInGroupResponse, error) {
+	return client.PostLargeImportInGroupWithContext(context.Background(), groupID, datasetDisplayName, nameConflict, skipReport, requestData)
+}
+
+// PostLargeImportInGroupWithContext is the same as PostLargeImportInGroup with the addition of a context to cancel the request
+func (client *Client) PostLargeImportInGroupWithContext(ctx context.Context, groupID string, datasetDisplayName string, nameConflict string, skipReport bool, requestData io.Reader) (*PostImportInGroupResponse, error) {
+
+	location, err := client.CreateTemporaryUploadLocationInGroupWithContext(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.UploadToTemporaryUploadLocationWithContext(ctx, location.URL, requestData)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.PostImportFromFileURLInGroupWithContext(ctx, groupID, datasetDisplayName, nameConflict, skipReport, location.URL)
+}
 
+func importQueryParams(datasetDisplayName string, nameConflict string, skipReport bool) url.Values {
 	queryParams := url.Values{}
 	if datasetDisplayName != "" {
 		queryParams.Add("datasetDisplayName", datasetDisplayName)
@@ -88,60 +260,64 @@ func (client *Client) PostImportInGroup(groupID string, datasetDisplayName strin
 	if skipReport {
 		queryParams.Add("skipReport", "true")
 	}
-
-	var respObj PostImportInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/imports?%s", url.PathEscape(groupID), queryParams.Encode())
-	err := client.doMultipartJSON("POST", url, requestData, &respObj)
-
-	return &respObj, err
+	return queryParams
 }
 
 // WaitForImportInGroupToSucceed waits until the specified import in group succeeds
 func (client *Client) WaitForImportInGroupToSucceed(groupID string, importID string, timeout time.Duration) (*GetImportInGroupResponse, error) {
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
-
-	started := time.Now()
-	for {
-		im, err := client.GetImportInGroup(groupID, importID)
-		if err != nil {
-			return nil, err
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return client.WaitForImportInGroupToSucceedWithContext(ctx, groupID, importID)
+}
 
-		if im.ImportState == "Succeeded" {
-			return im, nil
-		} else if im.ImportState != "Publishing" {
-			return im, fmt.Errorf("Import completed with invalid state '%s'", im.ImportState)
-		}
+// WaitForImportInGroupToSucceedWithContext waits until the specified import in group succeeds or the context is done
+func (client *Client) WaitForImportInGroupToSucceedWithContext(ctx context.Context, groupID string, importID string) (*GetImportInGroupResponse, error) {
+	poller := client.newPoller("Import")
+	poller.SucceededStates = []string{"Succeeded"}
+	poller.TransientStates = []string{"Publishing"}
 
-		now := <-ticker.C
-		if now.Sub(started) > timeout {
-			return nil, fmt.Errorf("Timed out waiting for import to complete. Import taking longer than %v seconds", timeout.Seconds())
+	var im *GetImportInGroupResponse
+	_, err := poller.Poll(ctx, func(ctx context.Context) (string, error) {
+		var err error
+		im, err = client.GetImportInGroupWithContext(ctx, groupID, importID)
+		if err != nil {
+			return "", err
 		}
+		return im.ImportState, nil
+	})
+	// imports that completed in a failed state are returned along with the error, so their details can be reported
+	if err != nil && (im == nil || im.ImportState == "" || im.ImportState == "Publishing") {
+		return nil, err
 	}
+	return im, err
 }
 
 // GetImportInGroup returns the import found within a group
 func (client *Client) GetImportInGroup(groupID string, importID string) (*GetImportInGroupResponse, error) {
+	return client.GetImportInGroupWithContext(context.Background(), groupID, importID)
+}
+
+// GetImportInGroupWithContext is the same as GetImportInGroup with the addition of a context to cancel the request
+func (client *Client) GetImportInGroupWithContext(ctx context.Context, groupID string, importID string) (*GetImportInGroupResponse, error) {
 
 	var respObj GetImportInGroupResponse
-	url := fmt.Sprintf(
-		"https://api.powerbi.com/v1.0/myorg/groups/%s/imports/%s",
-		url.PathEscape(groupID),
-		url.PathEscape(importID))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.groupURL(groupID, "/imports/%s", url.PathEscape(importID))
+	err := client.doJSON(ctx, "GET", url, nil, &respObj)
 
 	return &respObj, err
 }
 
 // GetImportsInGroup returns the imports found within a group
 func (client *Client) GetImportsInGroup(groupID string) (*GetImportsInGroupResponse, error) {
+	return client.GetImportsInGroupWithContext(context.Background(), groupID)
+}
+
+// GetImportsInGroupWithContext is the same as GetImportsInGroup with the addition of a context to cancel the request
+func (client *Client) GetImportsInGroupWithContext(ctx context.Context, groupID string) (*GetImportsInGroupResponse, error) {
 
 	var respObj GetImportsInGroupResponse
-	url := fmt.Sprintf(
-		"https://api.powerbi.com/v1.0/myorg/groups/%s/imports",
-		url.PathEscape(groupID))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.groupURL(groupID, "/imports")
+	err := client.getAllPages(ctx, url, 0, &respObj.Value)
 
 	return &respObj, err
 }
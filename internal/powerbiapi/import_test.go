@@ -0,0 +1,106 @@
+package powerbiapi
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestPostLargeImportInGroup(t *testing.T) {
+	defer func(blockSize int) { temporaryUploadBlockSize = blockSize }(temporaryUploadBlockSize)
+	temporaryUploadBlockSize = 4
+
+	blocks := map[string][]byte{}
+	var committed []byte
+	var importedFileURL string
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1.0/myorg/groups/group-id/imports/createTemporaryUploadLocation":
+			w.Write([]byte(`{"url":"` + server.URL + `/blob/upload.pbix?sv=2019&sig=signature","expirationTime":"2100-01-01T00:00:00Z"}`))
+
+		case r.URL.Path == "/blob/upload.pbix":
+			if r.Header.Get("Authorization") != "" {
+				t.Errorf("expected the bearer token to not be sent to blob storage")
+			}
+			if r.URL.Query().Get("sig") != "signature" {
+				t.Errorf("expected the SAS token to be sent to blob storage")
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			switch r.URL.Query().Get("comp") {
+			case "block":
+				blocks[r.URL.Query().Get("blockid")] = body
+			case "blocklist":
+				var blockList struct {
+					Latest []string
+				}
+				if err := xml.Unmarshal(body, &blockList); err != nil {
+					t.Errorf("invalid block list: %v", err)
+				}
+				for _, blockID := range blockList.Latest {
+					committed = append(committed, blocks[blockID]...)
+				}
+			}
+			w.WriteHeader(201)
+
+		case r.URL.Path == "/v1.0/myorg/groups/group-id/imports":
+			if r.URL.Query().Get("datasetDisplayName") != "large.pbix" {
+				t.Errorf("unexpected datasetDisplayName %s", r.URL.Query().Get("datasetDisplayName"))
+			}
+			var request PostImportFromFileURLInGroupRequest
+			json.NewDecoder(r.Body).Decode(&request)
+			importedFileURL = request.FileURL
+			w.Write([]byte(`{"id":"import-id"}`))
+
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, nil)
+
+	resp, err := client.PostLargeImportInGroup("group-id", "large.pbix", "CreateOrOverwrite", false, strings.NewReader("large pbix content"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.ID != "import-id" {
+		t.Fatalf("unexpected import id %s", resp.ID)
+	}
+	if len(blocks) != 5 {
+		t.Fatalf("expected content to be uploaded in 5 blocks but got %d", len(blocks))
+	}
+	if string(committed) != "large pbix content" {
+		t.Fatalf("unexpected committed blob content %q", committed)
+	}
+	if importedFileURL != server.URL+"/blob/upload.pbix?sv=2019&sig=signature" {
+		t.Fatalf("unexpected import file url %s", importedFileURL)
+	}
+}
+
+func TestBlobURL(t *testing.T) {
+	cases := []struct {
+		uploadURL string
+		values    url.Values
+		expected  string
+	}{
+		{"https://blob.example.com/upload.pbix?sv=2019&sig=a%2Bb%2Fc%3D", url.Values{"comp": {"blocklist"}}, "https://blob.example.com/upload.pbix?comp=blocklist&sig=a%2Bb%2Fc%3D&sv=2019"},
+		{"https://blob.example.com/upload.pbix", url.Values{"comp": {"block"}, "blockid": {"YmxvY2s="}}, "https://blob.example.com/upload.pbix?blockid=YmxvY2s%3D&comp=block"},
+	}
+	for _, c := range cases {
+		actual, err := blobURL(c.uploadURL, c.values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != c.expected {
+			t.Fatalf("expected blob url %s but got %s", c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,636 @@
+// Package powerbiapifake provides a hand-written fake of powerbiapi.API for unit testing code that calls Power BI.
+// Each method records the call and then calls the function field of the same name, methods without a function
+// return ErrNotImplemented so tests only need to stub the calls they expect
+package powerbiapifake
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+)
+
+// ErrNotImplemented is returned by methods whose function has not been set
+var ErrNotImplemented = errors.New("powerbiapifake: method not implemented")
+
+// Call is a method called on the fake and the arguments it was called with, excluding the context
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Client is a fake of powerbiapi.API
+type Client struct {
+	// Context is returned by BaseContext, a background context is returned when nil
+	Context context.Context
+	// AdminAPIs is returned by UseAdminAPIs
+	AdminAPIs bool
+
+	mux   sync.Mutex
+	calls []Call
+
+	// groups API
+	CreateGroupFunc            func(ctx context.Context, request powerbiapi.CreateGroupRequest) (*powerbiapi.CreateGroupResponse, error)
+	GetGroupsFunc              func(ctx context.Context, filter string, top int, skip int) (*powerbiapi.GetGroupsResponse, error)
+	GetGroupFunc               func(ctx context.Context, groupID string) (*powerbiapi.GetGroupResponse, error)
+	GetGroupByNameFunc         func(ctx context.Context, groupName string) (*powerbiapi.GetGroupResponse, error)
+	DeleteGroupFunc            func(ctx context.Context, groupID string) error
+	UpdateGroupFunc            func(ctx context.Context, groupID string, request powerbiapi.UpdateGroupRequest) error
+	GetGroupUsersFunc          func(ctx context.Context, groupID string) (*powerbiapi.GetGroupUsersResponse, error)
+	AddGroupUserFunc           func(ctx context.Context, groupID string, request powerbiapi.AddGroupUserRequest) error
+	UpdateGroupUserFunc        func(ctx context.Context, groupID string, request powerbiapi.UpdateGroupUserRequest) error
+	DeleteUserInGroupFunc      func(ctx context.Context, groupID string, userInfo string) error
+	RefreshUserPermissionsFunc func(ctx context.Context) error
+	UpdateGroupAsAdminFunc     func(ctx context.Context, groupID string, request powerbiapi.UpdateGroupAsAdminRequest) error
+	GetGroupAsAdminFunc        func(ctx context.Context, groupID string) (*powerbiapi.GetGroupAsAdminResponse, error)
+
+	// imports API
+	PostImportInGroupFunc                    func(ctx context.Context, groupID string, datasetDisplayName string, nameConflict string, skipReport bool, requestData io.Reader) (*powerbiapi.PostImportInGroupResponse, error)
+	CreateTemporaryUploadLocationInGroupFunc func(ctx context.Context, groupID string) (*powerbiapi.CreateTemporaryUploadLocationResponse, error)
+	UploadToTemporaryUploadLocationFunc      func(ctx context.Context, uploadURL string, content io.Reader) error
+	PostImportFromFileURLInGroupFunc         func(ctx context.Context, groupID string, datasetDisplayName string, nameConflict string, skipReport bool, fileURL string) (*powerbiapi.PostImportInGroupResponse, error)
+	PostLargeImportInGroupFunc               func(ctx context.Context, groupID string, datasetDisplayName string, nameConflict string, skipReport bool, requestData io.Reader) (*powerbiapi.PostImportInGroupResponse, error)
+	WaitForImportInGroupToSucceedFunc        func(ctx context.Context, groupID string, importID string) (*powerbiapi.GetImportInGroupResponse, error)
+	GetImportInGroupFunc                     func(ctx context.Context, groupID string, importID string) (*powerbiapi.GetImportInGroupResponse, error)
+	GetImportsInGroupFunc                    func(ctx context.Context, groupID string) (*powerbiapi.GetImportsInGroupResponse, error)
+
+	// datasets API
+	GetDatasetInGroupFunc            func(ctx context.Context, groupID string, datasetID string) (*powerbiapi.GetDatasetInGroupResponse, error)
+	GetDatasetsInGroupFunc           func(ctx context.Context, groupID string) (*powerbiapi.GetDatasetsInGroupResponse, error)
+	DeleteDatasetInGroupFunc         func(ctx context.Context, groupID string, datasetID string) error
+	GetParametersInGroupFunc         func(ctx context.Context, groupID string, datasetID string) (*powerbiapi.GetParametersInGroupResponse, error)
+	UpdateParametersInGroupFunc      func(ctx context.Context, groupID string, datasetID string, request powerbiapi.UpdateParametersInGroupRequest) error
+	GetDatasourcesInGroupFunc        func(ctx context.Context, groupID string, datasetID string) (*powerbiapi.GetDatasourcesInGroupResponse, error)
+	UpdateDatasourcesInGroupFunc     func(ctx context.Context, groupID string, datasetID string, request powerbiapi.UpdateDatasourcesInGroupRequest) error
+	GetRefreshScheduleInGroupFunc    func(ctx context.Context, groupID string, datasetID string) (*powerbiapi.GetRefreshScheduleInGroupResponse, error)
+	UpdateRefreshScheduleInGroupFunc func(ctx context.Context, groupID string, datasetID string, request powerbiapi.UpdateRefreshScheduleInGroupRequest) error
+	PostDatasetInGroupFunc           func(ctx context.Context, groupID string, defaultRetentionPolicy string, request powerbiapi.PostDatasetInGroupRequest) (*powerbiapi.PostDatasetInGroupResponse, error)
+	GetTablesFunc                    func(ctx context.Context, datasetID string) (*powerbiapi.GetTablesResponse, error)
+	PutTableInGroupFunc              func(ctx context.Context, groupID string, datasetID string, tableName string, request powerbiapi.PutTableInGroupRequest) error
+	PostRowsInGroupFunc              func(ctx context.Context, groupID string, datasetID string, tableName string, request powerbiapi.PostRowsInGroupRequest) error
+
+	// reports API
+	GetReportsInGroupFunc   func(ctx context.Context, groupID string) (*powerbiapi.GetReportsInGroupResponse, error)
+	GetReportInGroupFunc    func(ctx context.Context, groupID string, reportID string) (*powerbiapi.GetReportInGroupResponse, error)
+	DeleteReportInGroupFunc func(ctx context.Context, groupID string, reportID string) error
+	RebindReportInGroupFunc func(ctx context.Context, groupID string, reportID string, request powerbiapi.RebindReportInGroupRequest) error
+
+	// gateways API
+	CreateDatasourceFunc     func(ctx context.Context, gatewayId string, request powerbiapi.CreateDatasourceRequest) error
+	DeleteDatasourceFunc     func(ctx context.Context, gatewayId string, datasourceId string) error
+	DeleteDatasourceUserFunc func(ctx context.Context, gatewayId string, datasourceId string, emailAdress string) error
+	AddDatasourceUserFunc    func(ctx context.Context, gatewayId string, datasourceId string, request powerbiapi.AddDatasouceUserRequest) error
+	GetGatewaysFunc          func(ctx context.Context) (*powerbiapi.GetGatewaysResponse, error)
+	GetGatewayFunc           func(ctx context.Context, gatewayId string) (*powerbiapi.GetGatewaysResponseItem, error)
+	GetDatasourcesFunc       func(ctx context.Context, gatewayId string) (*powerbiapi.GetDatasourcesResponse, error)
+	GetDatasourceFunc        func(ctx context.Context, gatewayId string, datasourceId string) (*powerbiapi.GetDatasourcesResponseItem, error)
+	GetDatasourceStatusFunc  func(ctx context.Context, gatewayId string, datasourceId string) (*powerbiapi.GetDatasourceStatusResponse, error)
+	GetDatasourceUsersFunc   func(ctx context.Context, gatewayId string, datasourceId string) (*powerbiapi.GetDatasourceUsersResponse, error)
+
+	// capacities API
+	GroupAssignToCapacityFunc func(ctx context.Context, groupID string, request powerbiapi.GroupAssignToCapacityRequest) error
+	GetCapacitiesFunc         func(ctx context.Context) (*powerbiapi.GetCapacitiesResponse, error)
+
+	// profiles API
+	CreateProfileFunc func(ctx context.Context, request powerbiapi.CreateProfileRequest) (*powerbiapi.CreateProfileResponse, error)
+	GetProfilesFunc   func(ctx context.Context) (*powerbiapi.GetProfilesResponse, error)
+	GetProfileFunc    func(ctx context.Context, profileID string) (*powerbiapi.GetProfileResponse, error)
+	UpdateProfileFunc func(ctx context.Context, profileID string, request powerbiapi.UpdateProfileRequest) error
+	DeleteProfileFunc func(ctx context.Context, profileID string) error
+}
+
+var _ powerbiapi.API = (*Client)(nil)
+
+// Calls returns every call made to the fake in the order they were made
+func (client *Client) Calls() []Call {
+	client.mux.Lock()
+	defer client.mux.Unlock()
+	return append([]Call(nil), client.calls...)
+}
+
+// CallsTo returns the calls made to the method, such as "GetGroup"
+func (client *Client) CallsTo(method string) []Call {
+	var calls []Call
+	for _, call := range client.Calls() {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func (client *Client) record(method string, args ...interface{}) {
+	client.mux.Lock()
+	defer client.mux.Unlock()
+	client.calls = append(client.calls, Call{Method: method, Args: args})
+}
+
+// BaseContext returns Context, or a background context when Context is not set
+func (client *Client) BaseContext() context.Context {
+	if client.Context == nil {
+		return context.Background()
+	}
+	return client.Context
+}
+
+// UseAdminAPIs returns AdminAPIs
+func (client *Client) UseAdminAPIs() bool {
+	return client.AdminAPIs
+}
+
+// CreateGroupWithContext calls CreateGroupFunc
+func (client *Client) CreateGroupWithContext(ctx context.Context, request powerbiapi.CreateGroupRequest) (*powerbiapi.CreateGroupResponse, error) {
+	client.record("CreateGroup", request)
+	if client.CreateGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.CreateGroupFunc(ctx, request)
+}
+
+// GetGroupsWithContext calls GetGroupsFunc
+func (client *Client) GetGroupsWithContext(ctx context.Context, filter string, top int, skip int) (*powerbiapi.GetGroupsResponse, error) {
+	client.record("GetGroups", filter, top, skip)
+	if client.GetGroupsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetGroupsFunc(ctx, filter, top, skip)
+}
+
+// GetGroupWithContext calls GetGroupFunc
+func (client *Client) GetGroupWithContext(ctx context.Context, groupID string) (*powerbiapi.GetGroupResponse, error) {
+	client.record("GetGroup", groupID)
+	if client.GetGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetGroupFunc(ctx, groupID)
+}
+
+// GetGroupByNameWithContext calls GetGroupByNameFunc
+func (client *Client) GetGroupByNameWithContext(ctx context.Context, groupName string) (*powerbiapi.GetGroupResponse, error) {
+	client.record("GetGroupByName", groupName)
+	if client.GetGroupByNameFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetGroupByNameFunc(ctx, groupName)
+}
+
+// DeleteGroupWithContext calls DeleteGroupFunc
+func (client *Client) DeleteGroupWithContext(ctx context.Context, groupID string) error {
+	client.record("DeleteGroup", groupID)
+	if client.DeleteGroupFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.DeleteGroupFunc(ctx, groupID)
+}
+
+// GetGroupUsersWithContext calls GetGroupUsersFunc
+func (client *Client) GetGroupUsersWithContext(ctx context.Context, groupID string) (*powerbiapi.GetGroupUsersResponse, error) {
+	client.record("GetGroupUsers", groupID)
+	if client.GetGroupUsersFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetGroupUsersFunc(ctx, groupID)
+}
+
+// AddGroupUserWithContext calls AddGroupUserFunc
+func (client *Client) AddGroupUserWithContext(ctx context.Context, groupID string, request powerbiapi.AddGroupUserRequest) error {
+	client.record("AddGroupUser", groupID, request)
+	if client.AddGroupUserFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.AddGroupUserFunc(ctx, groupID, request)
+}
+
+// UpdateGroupUserWithContext calls UpdateGroupUserFunc
+func (client *Client) UpdateGroupUserWithContext(ctx context.Context, groupID string, request powerbiapi.UpdateGroupUserRequest) error {
+	client.record("UpdateGroupUser", groupID, request)
+	if client.UpdateGroupUserFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.UpdateGroupUserFunc(ctx, groupID, request)
+}
+
+// DeleteUserInGroupWithContext calls DeleteUserInGroupFunc
+func (client *Client) DeleteUserInGroupWithContext(ctx context.Context, groupID string, userInfo string) error {
+	client.record("DeleteUserInGroup", groupID, userInfo)
+	if client.DeleteUserInGroupFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.DeleteUserInGroupFunc(ctx, groupID, userInfo)
+}
+
+// RefreshUserPermissionsWithContext calls RefreshUserPermissionsFunc
+func (client *Client) RefreshUserPermissionsWithContext(ctx context.Context) error {
+	client.record("RefreshUserPermissions")
+	if client.RefreshUserPermissionsFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.RefreshUserPermissionsFunc(ctx)
+}
+
+// UpdateGroupWithContext calls UpdateGroupFunc
+func (client *Client) UpdateGroupWithContext(ctx context.Context, groupID string, request powerbiapi.UpdateGroupRequest) error {
+	client.record("UpdateGroup", groupID, request)
+	if client.UpdateGroupFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.UpdateGroupFunc(ctx, groupID, request)
+}
+
+// UpdateGroupAsAdminWithContext calls UpdateGroupAsAdminFunc
+func (client *Client) UpdateGroupAsAdminWithContext(ctx context.Context, groupID string, request powerbiapi.UpdateGroupAsAdminRequest) error {
+	client.record("UpdateGroupAsAdmin", groupID, request)
+	if client.UpdateGroupAsAdminFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.UpdateGroupAsAdminFunc(ctx, groupID, request)
+}
+
+// GetGroupAsAdminWithContext calls GetGroupAsAdminFunc
+func (client *Client) GetGroupAsAdminWithContext(ctx context.Context, groupID string) (*powerbiapi.GetGroupAsAdminResponse, error) {
+	client.record("GetGroupAsAdmin", groupID)
+	if client.GetGroupAsAdminFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetGroupAsAdminFunc(ctx, groupID)
+}
+
+// PostImportInGroupWithContext calls PostImportInGroupFunc
+func (client *Client) PostImportInGroupWithContext(ctx context.Context, groupID string, datasetDisplayName string, nameConflict string, skipReport bool, requestData io.Reader) (*powerbiapi.PostImportInGroupResponse, error) {
+	client.record("PostImportInGroup", groupID, datasetDisplayName, nameConflict, skipReport, requestData)
+	if client.PostImportInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.PostImportInGroupFunc(ctx, groupID, datasetDisplayName, nameConflict, skipReport, requestData)
+}
+
+// CreateTemporaryUploadLocationInGroupWithContext calls CreateTemporaryUploadLocationInGroupFunc
+func (client *Client) CreateTemporaryUploadLocationInGroupWithContext(ctx context.Context, groupID string) (*powerbiapi.CreateTemporaryUploadLocationResponse, error) {
+	client.record("CreateTemporaryUploadLocationInGroup", groupID)
+	if client.CreateTemporaryUploadLocationInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.CreateTemporaryUploadLocationInGroupFunc(ctx, groupID)
+}
+
+// UploadToTemporaryUploadLocationWithContext calls UploadToTemporaryUploadLocationFunc
+func (client *Client) UploadToTemporaryUploadLocationWithContext(ctx context.Context, uploadURL string, content io.Reader) error {
+	client.record("UploadToTemporaryUploadLocation", uploadURL, content)
+	if client.UploadToTemporaryUploadLocationFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.UploadToTemporaryUploadLocationFunc(ctx, uploadURL, content)
+}
+
+// PostImportFromFileURLInGroupWithContext calls PostImportFromFileURLInGroupFunc
+func (client *Client) PostImportFromFileURLInGroupWithContext(ctx context.Context, groupID string, datasetDisplayName string, nameConflict string, skipReport bool, fileURL string) (*powerbiapi.PostImportInGroupResponse, error) {
+	client.record("PostImportFromFileURLInGroup", groupID, datasetDisplayName, nameConflict, skipReport, fileURL)
+	if client.PostImportFromFileURLInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.PostImportFromFileURLInGroupFunc(ctx, groupID, datasetDisplayName, nameConflict, skipReport, fileURL)
+}
+
+// PostLargeImportInGroupWithContext calls PostLargeImportInGroupFunc
+func (client *Client) PostLargeImportInGroupWithContext(ctx context.Context, groupID string, datasetDisplayName string, nameConflict string, skipReport bool, requestData io.Reader) (*powerbiapi.PostImportInGroupResponse, error) {
+	client.record("PostLargeImportInGroup", groupID, datasetDisplayName, nameConflict, skipReport, requestData)
+	if client.PostLargeImportInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.PostLargeImportInGroupFunc(ctx, groupID, datasetDisplayName, nameConflict, skipReport, requestData)
+}
+
+// WaitForImportInGroupToSucceedWithContext calls WaitForImportInGroupToSucceedFunc
+func (client *Client) WaitForImportInGroupToSucceedWithContext(ctx context.Context, groupID string, importID string) (*powerbiapi.GetImportInGroupResponse, error) {
+	client.record("WaitForImportInGroupToSucceed", groupID, importID)
+	if client.WaitForImportInGroupToSucceedFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.WaitForImportInGroupToSucceedFunc(ctx, groupID, importID)
+}
+
+// GetImportInGroupWithContext calls GetImportInGroupFunc
+func (client *Client) GetImportInGroupWithContext(ctx context.Context, groupID string, importID string) (*powerbiapi.GetImportInGroupResponse, error) {
+	client.record("GetImportInGroup", groupID, importID)
+	if client.GetImportInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetImportInGroupFunc(ctx, groupID, importID)
+}
+
+// GetImportsInGroupWithContext calls GetImportsInGroupFunc
+func (client *Client) GetImportsInGroupWithContext(ctx context.Context, groupID string) (*powerbiapi.GetImportsInGroupResponse, error) {
+	client.record("GetImportsInGroup", groupID)
+	if client.GetImportsInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetImportsInGroupFunc(ctx, groupID)
+}
+
+// GetDatasetInGroupWithContext calls GetDatasetInGroupFunc
+func (client *Client) GetDatasetInGroupWithContext(ctx context.Context, groupID string, datasetID string) (*powerbiapi.GetDatasetInGroupResponse, error) {
+	client.record("GetDatasetInGroup", groupID, datasetID)
+	if client.GetDatasetInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetDatasetInGroupFunc(ctx, groupID, datasetID)
+}
+
+// GetDatasetsInGroupWithContext calls GetDatasetsInGroupFunc
+func (client *Client) GetDatasetsInGroupWithContext(ctx context.Context, groupID string) (*powerbiapi.GetDatasetsInGroupResponse, error) {
+	client.record("GetDatasetsInGroup", groupID)
+	if client.GetDatasetsInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetDatasetsInGroupFunc(ctx, groupID)
+}
+
+// DeleteDatasetInGroupWithContext calls DeleteDatasetInGroupFunc
+func (client *Client) DeleteDatasetInGroupWithContext(ctx context.Context, groupID string, datasetID string) error {
+	client.record("DeleteDatasetInGroup", groupID, datasetID)
+	if client.DeleteDatasetInGroupFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.DeleteDatasetInGroupFunc(ctx, groupID, datasetID)
+}
+
+// GetParametersInGroupWithContext calls GetParametersInGroupFunc
+func (client *Client) GetParametersInGroupWithContext(ctx context.Context, groupID string, datasetID string) (*powerbiapi.GetParametersInGroupResponse, error) {
+	client.record("GetParametersInGroup", groupID, datasetID)
+	if client.GetParametersInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetParametersInGroupFunc(ctx, groupID, datasetID)
+}
+
+// UpdateParametersInGroupWithContext calls UpdateParametersInGroupFunc
+func (client *Client) UpdateParametersInGroupWithContext(ctx context.Context, groupID string, datasetID string, request powerbiapi.UpdateParametersInGroupRequest) error {
+	client.record("UpdateParametersInGroup", groupID, datasetID, request)
+	if client.UpdateParametersInGroupFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.UpdateParametersInGroupFunc(ctx, groupID, datasetID, request)
+}
+
+// GetDatasourcesInGroupWithContext calls GetDatasourcesInGroupFunc
+func (client *Client) GetDatasourcesInGroupWithContext(ctx context.Context, groupID string, datasetID string) (*powerbiapi.GetDatasourcesInGroupResponse, error) {
+	client.record("GetDatasourcesInGroup", groupID, datasetID)
+	if client.GetDatasourcesInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetDatasourcesInGroupFunc(ctx, groupID, datasetID)
+}
+
+// UpdateDatasourcesInGroupWithContext calls UpdateDatasourcesInGroupFunc
+func (client *Client) UpdateDatasourcesInGroupWithContext(ctx context.Context, groupID string, datasetID string, request powerbiapi.UpdateDatasourcesInGroupRequest) error {
+	client.record("UpdateDatasourcesInGroup", groupID, datasetID, request)
+	if client.UpdateDatasourcesInGroupFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.UpdateDatasourcesInGroupFunc(ctx, groupID, datasetID, request)
+}
+
+// GetRefreshScheduleInGroupWithContext calls GetRefreshScheduleInGroupFunc
+func (client *Client) GetRefreshScheduleInGroupWithContext(ctx context.Context, groupID string, datasetID string) (*powerbiapi.GetRefreshScheduleInGroupResponse, error) {
+	client.record("GetRefreshScheduleInGroup", groupID, datasetID)
+	if client.GetRefreshScheduleInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetRefreshScheduleInGroupFunc(ctx, groupID, datasetID)
+}
+
+// UpdateRefreshScheduleInGroupWithContext calls UpdateRefreshScheduleInGroupFunc
+func (client *Client) UpdateRefreshScheduleInGroupWithContext(ctx context.Context, groupID string, datasetID string, request powerbiapi.UpdateRefreshScheduleInGroupRequest) error {
+	client.record("UpdateRefreshScheduleInGroup", groupID, datasetID, request)
+	if client.UpdateRefreshScheduleInGroupFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.UpdateRefreshScheduleInGroupFunc(ctx, groupID, datasetID, request)
+}
+
+// PostDatasetInGroupWithContext calls PostDatasetInGroupFunc
+func (client *Client) PostDatasetInGroupWithContext(ctx context.Context, groupID string, defaultRetentionPolicy string, request powerbiapi.PostDatasetInGroupRequest) (*powerbiapi.PostDatasetInGroupResponse, error) {
+	client.record("PostDatasetInGroup", groupID, defaultRetentionPolicy, request)
+	if client.PostDatasetInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.PostDatasetInGroupFunc(ctx, groupID, defaultRetentionPolicy, request)
+}
+
+// GetTablesWithContext calls GetTablesFunc
+func (client *Client) GetTablesWithContext(ctx context.Context, datasetID string) (*powerbiapi.GetTablesResponse, error) {
+	client.record("GetTables", datasetID)
+	if client.GetTablesFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetTablesFunc(ctx, datasetID)
+}
+
+// PutTableInGroupWithContext calls PutTableInGroupFunc
+func (client *Client) PutTableInGroupWithContext(ctx context.Context, groupID string, datasetID string, tableName string, request powerbiapi.PutTableInGroupRequest) error {
+	client.record("PutTableInGroup", groupID, datasetID, tableName, request)
+	if client.PutTableInGroupFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.PutTableInGroupFunc(ctx, groupID, datasetID, tableName, request)
+}
+
+// PostRowsInGroupWithContext calls PostRowsInGroupFunc
+func (client *Client) PostRowsInGroupWithContext(ctx context.Context, groupID string, datasetID string, tableName string, request powerbiapi.PostRowsInGroupRequest) error {
+	client.record("PostRowsInGroup", groupID, datasetID, tableName, request)
+	if client.PostRowsInGroupFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.PostRowsInGroupFunc(ctx, groupID, datasetID, tableName, request)
+}
+
+// GetReportsInGroupWithContext calls GetReportsInGroupFunc
+func (client *Client) GetReportsInGroupWithContext(ctx context.Context, groupID string) (*powerbiapi.GetReportsInGroupResponse, error) {
+	client.record("GetReportsInGroup", groupID)
+	if client.GetReportsInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetReportsInGroupFunc(ctx, groupID)
+}
+
+// GetReportInGroupWithContext calls GetReportInGroupFunc
+func (client *Client) GetReportInGroupWithContext(ctx context.Context, groupID string, reportID string) (*powerbiapi.GetReportInGroupResponse, error) {
+	client.record("GetReportInGroup", groupID, reportID)
+	if client.GetReportInGroupFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetReportInGroupFunc(ctx, groupID, reportID)
+}
+
+// DeleteReportInGroupWithContext calls DeleteReportInGroupFunc
+func (client *Client) DeleteReportInGroupWithContext(ctx context.Context, groupID string, reportID string) error {
+	client.record("DeleteReportInGroup", groupID, reportID)
+	if client.DeleteReportInGroupFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.DeleteReportInGroupFunc(ctx, groupID, reportID)
+}
+
+// RebindReportInGroupWithContext calls RebindReportInGroupFunc
+func (client *Client) RebindReportInGroupWithContext(ctx context.Context, groupID string, reportID string, request powerbiapi.RebindReportInGroupRequest) error {
+	client.record("RebindReportInGroup", groupID, reportID, request)
+	if client.RebindReportInGroupFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.RebindReportInGroupFunc(ctx, groupID, reportID, request)
+}
+
+// CreateDatasourceWithContext calls CreateDatasourceFunc
+func (client *Client) CreateDatasourceWithContext(ctx context.Context, gatewayId string, request powerbiapi.CreateDatasourceRequest) error {
+	client.record("CreateDatasource", gatewayId, request)
+	if client.CreateDatasourceFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.CreateDatasourceFunc(ctx, gatewayId, request)
+}
+
+// DeleteDatasourceWithContext calls DeleteDatasourceFunc
+func (client *Client) DeleteDatasourceWithContext(ctx context.Context, gatewayId string, datasourceId string) error {
+	client.record("DeleteDatasource", gatewayId, datasourceId)
+	if client.DeleteDatasourceFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.DeleteDatasourceFunc(ctx, gatewayId, datasourceId)
+}
+
+// DeleteDatasourceUserWithContext calls DeleteDatasourceUserFunc
+func (client *Client) DeleteDatasourceUserWithContext(ctx context.Context, gatewayId string, datasourceId string, emailAdress string) error {
+	client.record("DeleteDatasourceUser", gatewayId, datasourceId, emailAdress)
+	if client.DeleteDatasourceUserFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.DeleteDatasourceUserFunc(ctx, gatewayId, datasourceId, emailAdress)
+}
+
+// AddDatasourceUserWithContext calls AddDatasourceUserFunc
+func (client *Client) AddDatasourceUserWithContext(ctx context.Context, gatewayId string, datasourceId string, request powerbiapi.AddDatasouceUserRequest) error {
+	client.record("AddDatasourceUser", gatewayId, datasourceId, request)
+	if client.AddDatasourceUserFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.AddDatasourceUserFunc(ctx, gatewayId, datasourceId, request)
+}
+
+// GetGatewaysWithContext calls GetGatewaysFunc
+func (client *Client) GetGatewaysWithContext(ctx context.Context) (*powerbiapi.GetGatewaysResponse, error) {
+	client.record("GetGateways")
+	if client.GetGatewaysFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetGatewaysFunc(ctx)
+}
+
+// GetGatewayWithContext calls GetGatewayFunc
+func (client *Client) GetGatewayWithContext(ctx context.Context, gatewayId string) (*powerbiapi.GetGatewaysResponseItem, error) {
+	client.record("GetGateway", gatewayId)
+	if client.GetGatewayFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetGatewayFunc(ctx, gatewayId)
+}
+
+// GetDatasourcesWithContext calls GetDatasourcesFunc
+func (client *Client) GetDatasourcesWithContext(ctx context.Context, gatewayId string) (*powerbiapi.GetDatasourcesResponse, error) {
+	client.record("GetDatasources", gatewayId)
+	if client.GetDatasourcesFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetDatasourcesFunc(ctx, gatewayId)
+}
+
+// GetDatasourceWithContext calls GetDatasourceFunc
+func (client *Client) GetDatasourceWithContext(ctx context.Context, gatewayId string, datasourceId string) (*powerbiapi.GetDatasourcesResponseItem, error) {
+	client.record("GetDatasource", gatewayId, datasourceId)
+	if client.GetDatasourceFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetDatasourceFunc(ctx, gatewayId, datasourceId)
+}
+
+// GetDatasourceStatusWithContext calls GetDatasourceStatusFunc
+func (client *Client) GetDatasourceStatusWithContext(ctx context.Context, gatewayId string, datasourceId string) (*powerbiapi.GetDatasourceStatusResponse, error) {
+	client.record("GetDatasourceStatus", gatewayId, datasourceId)
+	if client.GetDatasourceStatusFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetDatasourceStatusFunc(ctx, gatewayId, datasourceId)
+}
+
+// GetDatasourceUsersWithContext calls GetDatasourceUsersFunc
+func (client *Client) GetDatasourceUsersWithContext(ctx context.Context, gatewayId string, datasourceId string) (*powerbiapi.GetDatasourceUsersResponse, error) {
+	client.record("GetDatasourceUsers", gatewayId, datasourceId)
+	if client.GetDatasourceUsersFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetDatasourceUsersFunc(ctx, gatewayId, datasourceId)
+}
+
+// GroupAssignToCapacityWithContext calls GroupAssignToCapacityFunc
+func (client *Client) GroupAssignToCapacityWithContext(ctx context.Context, groupID string, request powerbiapi.GroupAssignToCapacityRequest) error {
+	client.record("GroupAssignToCapacity", groupID, request)
+	if client.GroupAssignToCapacityFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.GroupAssignToCapacityFunc(ctx, groupID, request)
+}
+
+// GetCapacitiesWithContext calls GetCapacitiesFunc
+func (client *Client) GetCapacitiesWithContext(ctx context.Context) (*powerbiapi.GetCapacitiesResponse, error) {
+	client.record("GetCapacities")
+	if client.GetCapacitiesFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetCapacitiesFunc(ctx)
+}
+
+// CreateProfileWithContext calls CreateProfileFunc
+func (client *Client) CreateProfileWithContext(ctx context.Context, request powerbiapi.CreateProfileRequest) (*powerbiapi.CreateProfileResponse, error) {
+	client.record("CreateProfile", request)
+	if client.CreateProfileFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.CreateProfileFunc(ctx, request)
+}
+
+// GetProfilesWithContext calls GetProfilesFunc
+func (client *Client) GetProfilesWithContext(ctx context.Context) (*powerbiapi.GetProfilesResponse, error) {
+	client.record("GetProfiles")
+	if client.GetProfilesFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetProfilesFunc(ctx)
+}
+
+// GetProfileWithContext calls GetProfileFunc
+func (client *Client) GetProfileWithContext(ctx context.Context, profileID string) (*powerbiapi.GetProfileResponse, error) {
+	client.record("GetProfile", profileID)
+	if client.GetProfileFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return client.GetProfileFunc(ctx, profileID)
+}
+
+// UpdateProfileWithContext calls UpdateProfileFunc
+func (client *Client) UpdateProfileWithContext(ctx context.Context, profileID string, request powerbiapi.UpdateProfileRequest) error {
+	client.record("UpdateProfile", profileID, request)
+	if client.UpdateProfileFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.UpdateProfileFunc(ctx, profileID, request)
+}
+
+// DeleteProfileWithContext calls DeleteProfileFunc
+func (client *Client) DeleteProfileWithContext(ctx context.Context, profileID string) error {
+	client.record("DeleteProfile", profileID)
+	if client.DeleteProfileFunc == nil {
+		return ErrNotImplemented
+	}
+	return client.DeleteProfileFunc(ctx, profileID)
+}
@@ -0,0 +1,354 @@
+package powerbiapitest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+)
+
+func defaultRefreshSchedule() powerbiapi.GetRefreshScheduleInGroupResponse {
+	return powerbiapi.GetRefreshScheduleInGroupResponse{
+		Enabled:         false,
+		Days:            []string{},
+		Times:           []string{},
+		LocalTimeZoneID: "UTC",
+		NotifyOption:    "MailOnFailure",
+	}
+}
+
+func (dataset *dataset) toResponse() powerbiapi.GetDatasetInGroupResponse {
+	return powerbiapi.GetDatasetInGroupResponse{
+		ID:                dataset.ID,
+		Name:              dataset.Name,
+		AddRowsAPIEnabled: dataset.AddRowsAPIEnabled,
+		ConfiguredBy:      "fake.user@contoso.com",
+		IsRefreshable:     !dataset.AddRowsAPIEnabled,
+		TargetStorageMode: "Abf",
+	}
+}
+
+func (server *Server) getDatasets(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+
+	items := []powerbiapi.GetDatasetsInGroupResponseItem{}
+	for _, dataset := range group.Datasets {
+		item := dataset.toResponse()
+		items = append(items, powerbiapi.GetDatasetsInGroupResponseItem{
+			ID:                item.ID,
+			Name:              item.Name,
+			AddRowsAPIEnabled: item.AddRowsAPIEnabled,
+			ConfiguredBy:      item.ConfiguredBy,
+			IsRefreshable:     item.IsRefreshable,
+			TargetStorageMode: item.TargetStorageMode,
+		})
+	}
+	writeJSON(w, http.StatusOK, powerbiapi.GetDatasetsInGroupResponse{
+		Value: items,
+	})
+}
+
+func (server *Server) getDataset(w http.ResponseWriter, r *http.Request, params []string) {
+	_, dataset := server.findGroupAndDataset(w, params[0], params[1])
+	if dataset == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, dataset.toResponse())
+}
+
+// deleteDataset deletes the dataset along with the reports bound to it, as Power BI does
+func (server *Server) deleteDataset(w http.ResponseWriter, r *http.Request, params []string) {
+	group, deleted := server.findGroupAndDataset(w, params[0], params[1])
+	if deleted == nil {
+		return
+	}
+
+	datasets := group.Datasets[:0]
+	for _, dataset := range group.Datasets {
+		if dataset != deleted {
+			datasets = append(datasets, dataset)
+		}
+	}
+	group.Datasets = datasets
+
+	reports := group.Reports[:0]
+	for _, report := range group.Reports {
+		if report.DatasetID != deleted.ID {
+			reports = append(reports, report)
+		}
+	}
+	group.Reports = reports
+
+	group.removeOrphanedImports()
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (server *Server) getParameters(w http.ResponseWriter, r *http.Request, params []string) {
+	_, dataset := server.findGroupAndDataset(w, params[0], params[1])
+	if dataset == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, powerbiapi.GetParametersInGroupResponse{
+		Value: append([]powerbiapi.GetParametersInGroupResponseItem{}, dataset.Parameters...),
+	})
+}
+
+func (server *Server) updateParameters(w http.ResponseWriter, r *http.Request, params []string) {
+	_, dataset := server.findGroupAndDataset(w, params[0], params[1])
+	if dataset == nil {
+		return
+	}
+	var request powerbiapi.UpdateParametersInGroupRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	// validate every parameter before updating so a failed request has no effect
+	indexes := make([]int, len(request.UpdateDetails))
+	for i, updateDetail := range request.UpdateDetails {
+		indexes[i] = -1
+		for j, parameter := range dataset.Parameters {
+			if parameter.Name == updateDetail.Name {
+				indexes[i] = j
+			}
+		}
+		if indexes[i] < 0 {
+			writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Parameter %s does not exist in the dataset", updateDetail.Name))
+			return
+		}
+	}
+	for i, updateDetail := range request.UpdateDetails {
+		dataset.Parameters[indexes[i]].CurrentValue = updateDetail.NewValue
+	}
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (server *Server) getDatasources(w http.ResponseWriter, r *http.Request, params []string) {
+	_, dataset := server.findGroupAndDataset(w, params[0], params[1])
+	if dataset == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, powerbiapi.GetDatasourcesInGroupResponse{
+		Value: append([]powerbiapi.GetDatasourcesInGroupResponseItem{}, dataset.Datasources...),
+	})
+}
+
+func (server *Server) updateDatasources(w http.ResponseWriter, r *http.Request, params []string) {
+	_, dataset := server.findGroupAndDataset(w, params[0], params[1])
+	if dataset == nil {
+		return
+	}
+	var request powerbiapi.UpdateDatasourcesInGroupRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	for _, updateDetail := range request.UpdateDetails {
+		matched := false
+		for i := range dataset.Datasources {
+			datasource := &dataset.Datasources[i]
+			if !matchesDatasourceSelector(*datasource, updateDetail.DatasourceSelector) {
+				continue
+			}
+			matched = true
+			if updateDetail.ConnectionDetails.URL != nil {
+				datasource.ConnectionDetails.URL = stringPtr(*updateDetail.ConnectionDetails.URL)
+			}
+			if updateDetail.ConnectionDetails.Server != nil {
+				datasource.ConnectionDetails.Server = stringPtr(*updateDetail.ConnectionDetails.Server)
+			}
+			if updateDetail.ConnectionDetails.Database != nil {
+				datasource.ConnectionDetails.Database = stringPtr(*updateDetail.ConnectionDetails.Database)
+			}
+		}
+		if !matched {
+			writeError(w, http.StatusBadRequest, "InvalidRequest", "Datasource selector did not match any datasource in the dataset")
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, nil)
+}
+
+// matchesDatasourceSelector determines if the datasource matches the type and every connection detail set on the selector
+func matchesDatasourceSelector(datasource powerbiapi.GetDatasourcesInGroupResponseItem, selector powerbiapi.UpdateDatasourcesInGroupRequestItemDatasourceSelector) bool {
+	if !strings.EqualFold(datasource.DatasourceType, selector.DatasourceType) {
+		return false
+	}
+	matches := func(selectorValue *string, value *string) bool {
+		return selectorValue == nil || (value != nil && strings.EqualFold(*selectorValue, *value))
+	}
+	return matches(selector.ConnectionDetails.URL, datasource.ConnectionDetails.URL) &&
+		matches(selector.ConnectionDetails.Server, datasource.ConnectionDetails.Server) &&
+		matches(selector.ConnectionDetails.Database, datasource.ConnectionDetails.Database)
+}
+
+func (server *Server) getRefreshSchedule(w http.ResponseWriter, r *http.Request, params []string) {
+	_, dataset := server.findGroupAndDataset(w, params[0], params[1])
+	if dataset == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, dataset.RefreshSchedule)
+}
+
+func (server *Server) updateRefreshSchedule(w http.ResponseWriter, r *http.Request, params []string) {
+	_, dataset := server.findGroupAndDataset(w, params[0], params[1])
+	if dataset == nil {
+		return
+	}
+	var request powerbiapi.UpdateRefreshScheduleInGroupRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	schedule := dataset.RefreshSchedule
+	if request.Value.Enabled != nil {
+		schedule.Enabled = *request.Value.Enabled
+	}
+	if request.Value.Days != nil {
+		schedule.Days = append([]string{}, *request.Value.Days...)
+	}
+	if request.Value.Times != nil {
+		schedule.Times = append([]string{}, *request.Value.Times...)
+	}
+	if request.Value.LocalTimeZoneID != nil {
+		schedule.LocalTimeZoneID = *request.Value.LocalTimeZoneID
+	}
+	if request.Value.NotifyOption != nil {
+		schedule.NotifyOption = *request.Value.NotifyOption
+	}
+	if schedule.Enabled && len(schedule.Times) == 0 {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "An enabled refresh schedule requires at least one time")
+		return
+	}
+
+	dataset.RefreshSchedule = schedule
+	writeJSON(w, http.StatusOK, nil)
+}
+
+// postDataset creates a push dataset
+func (server *Server) postDataset(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+	var request powerbiapi.PostDatasetInGroupRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	created := &dataset{
+		ID:                server.newID(),
+		Name:              request.Name,
+		AddRowsAPIEnabled: true,
+		Parameters:        []powerbiapi.GetParametersInGroupResponseItem{},
+		Datasources:       []powerbiapi.GetDatasourcesInGroupResponseItem{},
+		RefreshSchedule:   defaultRefreshSchedule(),
+	}
+	for _, requestTable := range request.Tables {
+		createdTable := &table{
+			Name: requestTable.Name,
+		}
+		for _, column := range requestTable.Columns {
+			createdTable.Columns = append(createdTable.Columns, powerbiapi.PutTableInGroupRequestTableColumn(column))
+		}
+		created.Tables = append(created.Tables, createdTable)
+	}
+	group.Datasets = append(group.Datasets, created)
+
+	writeJSON(w, http.StatusCreated, powerbiapi.PostDatasetInGroupResponse{
+		ID:   created.ID,
+		Name: created.Name,
+	})
+}
+
+// getTables returns the tables of a push dataset, the dataset is looked up across every group and My workspace
+func (server *Server) getTables(w http.ResponseWriter, r *http.Request, params []string) {
+	for _, group := range append([]*group{server.myWorkspace}, server.groups...) {
+		dataset := group.findDataset(params[0])
+		if dataset == nil {
+			continue
+		}
+		if !dataset.AddRowsAPIEnabled {
+			writeError(w, http.StatusNotFound, "ItemNotFound", fmt.Sprintf("Dataset %s is not a push dataset", params[0]))
+			return
+		}
+
+		tables := []powerbiapi.GetTablesResponseTable{}
+		for _, table := range dataset.Tables {
+			tables = append(tables, powerbiapi.GetTablesResponseTable{
+				Name: table.Name,
+			})
+		}
+		writeJSON(w, http.StatusOK, powerbiapi.GetTablesResponse{
+			Value: tables,
+		})
+		return
+	}
+	writeError(w, http.StatusNotFound, "ItemNotFound", fmt.Sprintf("Dataset %s is not found", params[0]))
+}
+
+func (dataset *dataset) findTable(w http.ResponseWriter, tableName string) *table {
+	for _, table := range dataset.Tables {
+		if strings.EqualFold(table.Name, tableName) {
+			return table
+		}
+	}
+	writeError(w, http.StatusNotFound, "ItemNotFound", fmt.Sprintf("Table %s is not found in the dataset", tableName))
+	return nil
+}
+
+func (server *Server) putTable(w http.ResponseWriter, r *http.Request, params []string) {
+	_, dataset := server.findGroupAndDataset(w, params[0], params[1])
+	if dataset == nil {
+		return
+	}
+	existing := dataset.findTable(w, params[2])
+	if existing == nil {
+		return
+	}
+	var request powerbiapi.PutTableInGroupRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	existing.Columns = append([]powerbiapi.PutTableInGroupRequestTableColumn{}, request.Columns...)
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (server *Server) postRows(w http.ResponseWriter, r *http.Request, params []string) {
+	_, dataset := server.findGroupAndDataset(w, params[0], params[1])
+	if dataset == nil {
+		return
+	}
+	existing := dataset.findTable(w, params[2])
+	if existing == nil {
+		return
+	}
+	var request powerbiapi.PostRowsInGroupRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	for _, row := range request.Rows {
+		for columnName := range row {
+			if !existing.hasColumn(columnName) {
+				writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Column %s does not exist in table %s", columnName, existing.Name))
+				return
+			}
+		}
+	}
+	existing.Rows = append(existing.Rows, request.Rows...)
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (table *table) hasColumn(columnName string) bool {
+	for _, column := range table.Columns {
+		if strings.EqualFold(column.Name, columnName) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,299 @@
+package powerbiapitest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+)
+
+// groupFilterRegex matches the OData filters the client uses to find groups
+var groupFilterRegex = regexp.MustCompile(`^(id|name) eq '(.*)'$`)
+
+func (group *group) toResponseItem() powerbiapi.GetGroupsResponseItem {
+	return powerbiapi.GetGroupsResponseItem{
+		ID:                    group.ID,
+		Name:                  group.Name,
+		IsOnDedicatedCapacity: group.CapacityID != "",
+		CapacityID:            group.CapacityID,
+	}
+}
+
+func (server *Server) createGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	var request powerbiapi.CreateGroupRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	for _, existing := range server.groups {
+		if strings.EqualFold(existing.Name, request.Name) {
+			writeError(w, http.StatusConflict, "PowerBIEntityAlreadyExists", fmt.Sprintf("Workspace %s already exists", request.Name))
+			return
+		}
+	}
+
+	group := &group{
+		ID:        server.newID(),
+		Name:      request.Name,
+		ProfileID: requestProfileID(r),
+	}
+	server.groups = append(server.groups, group)
+
+	item := group.toResponseItem()
+	writeJSON(w, http.StatusOK, powerbiapi.CreateGroupResponse{
+		ID:                    item.ID,
+		Name:                  item.Name,
+		IsOnDedicatedCapacity: item.IsOnDedicatedCapacity,
+		CapacityID:            item.CapacityID,
+	})
+}
+
+func (server *Server) getGroups(w http.ResponseWriter, r *http.Request, params []string) {
+	query := r.URL.Query()
+
+	var filterField, filterValue string
+	if filter := query.Get("$filter"); filter != "" {
+		match := groupFilterRegex.FindStringSubmatch(filter)
+		if match == nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Unsupported filter %s", filter))
+			return
+		}
+		filterField, filterValue = match[1], match[2]
+	}
+
+	// profiles only see the workspaces they created
+	profileID := requestProfileID(r)
+	items := []powerbiapi.GetGroupsResponseItem{}
+	for _, group := range server.groups {
+		if !strings.EqualFold(group.ProfileID, profileID) {
+			continue
+		}
+		if (filterField == "id" && group.ID != filterValue) || (filterField == "name" && group.Name != filterValue) {
+			continue
+		}
+		items = append(items, group.toResponseItem())
+	}
+
+	start, end := pageBounds(query, len(items))
+	writeJSON(w, http.StatusOK, powerbiapi.GetGroupsResponse{
+		Value: items[start:end],
+	})
+}
+
+// pageBounds returns the range of a list of length items selected by $skip and $top
+func pageBounds(query url.Values, length int) (int, int) {
+	start, end := 0, length
+	if skip, err := strconv.Atoi(query.Get("$skip")); err == nil && skip > 0 {
+		start = skip
+		if start > length {
+			start = length
+		}
+	}
+	if top, err := strconv.Atoi(query.Get("$top")); err == nil && top >= 0 && start+top < end {
+		end = start + top
+	}
+	return start, end
+}
+
+func (server *Server) deleteGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	for i, group := range server.groups {
+		if group.ID == params[0] {
+			server.groups = append(server.groups[:i], server.groups[i+1:]...)
+			writeJSON(w, http.StatusOK, nil)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "PowerBIEntityNotFound", fmt.Sprintf("Workspace %s not found", params[0]))
+}
+
+func (server *Server) updateGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+	// the workspace API does not update descriptions, any description in the request is ignored
+	var request powerbiapi.UpdateGroupRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	server.applyGroupUpdate(w, group, request.Name, nil)
+}
+
+func (server *Server) getGroupAsAdmin(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+	item := group.toResponseItem()
+	writeJSON(w, http.StatusOK, powerbiapi.GetGroupAsAdminResponse{
+		ID:                    item.ID,
+		Name:                  item.Name,
+		Description:           group.Description,
+		Type:                  "Workspace",
+		State:                 "Active",
+		IsOnDedicatedCapacity: item.IsOnDedicatedCapacity,
+		CapacityID:            item.CapacityID,
+	})
+}
+
+func (server *Server) updateGroupAsAdmin(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+	var request powerbiapi.UpdateGroupAsAdminRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	server.applyGroupUpdate(w, group, request.Name, request.Description)
+}
+
+// applyGroupUpdate updates the name and description of the group when they are set, names must remain unique
+func (server *Server) applyGroupUpdate(w http.ResponseWriter, group *group, name string, description *string) {
+	for _, existing := range server.groups {
+		if existing != group && name != "" && strings.EqualFold(existing.Name, name) {
+			writeError(w, http.StatusConflict, "PowerBIEntityAlreadyExists", fmt.Sprintf("Workspace %s already exists", name))
+			return
+		}
+	}
+	if name != "" {
+		group.Name = name
+	}
+	if description != nil {
+		group.Description = *description
+	}
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (server *Server) getGroupUsers(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+	start, end := pageBounds(r.URL.Query(), len(group.Users))
+	writeJSON(w, http.StatusOK, powerbiapi.GetGroupUsersResponse{
+		Value: append([]powerbiapi.GetGroupUsersResponseItem{}, group.Users[start:end]...),
+	})
+}
+
+func (server *Server) addGroupUser(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+	var request powerbiapi.AddGroupUserRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	identifier := request.Identifier
+	if identifier == "" {
+		identifier = request.EmailAddress
+	}
+	if identifier == "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "Either identifier or emailAddress must be provided")
+		return
+	}
+	if group.findUser(identifier) >= 0 {
+		writeError(w, http.StatusConflict, "AddingAlreadyExistsGroupUserNotSupportedError", fmt.Sprintf("User %s already has access to the workspace", identifier))
+		return
+	}
+
+	displayName := request.DisplayName
+	if displayName == "" {
+		displayName = strings.SplitN(identifier, "@", 2)[0]
+	}
+	group.Users = append(group.Users, powerbiapi.GetGroupUsersResponseItem{
+		DisplayName:          displayName,
+		EmailAddress:         request.EmailAddress,
+		GroupUserAccessRight: request.GroupUserAccessRight,
+		Identifier:           identifier,
+		PrincipalType:        request.PrincipalType,
+	})
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (server *Server) updateGroupUser(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+	var request powerbiapi.UpdateGroupUserRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	identifier := request.Identifier
+	if identifier == "" {
+		identifier = request.EmailAddress
+	}
+	index := group.findUser(identifier)
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "ItemNotFound", fmt.Sprintf("User %s does not have access to the workspace", identifier))
+		return
+	}
+	group.Users[index].GroupUserAccessRight = request.GroupUserAccessRight
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (server *Server) deleteGroupUser(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+	index := group.findUser(params[1])
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "ItemNotFound", fmt.Sprintf("User %s does not have access to the workspace", params[1]))
+		return
+	}
+	group.Users = append(group.Users[:index], group.Users[index+1:]...)
+	writeJSON(w, http.StatusOK, nil)
+}
+
+// findUser returns the index of the user with the identifier or email address, or -1 if they do not have access
+func (group *group) findUser(identifier string) int {
+	for i, user := range group.Users {
+		if strings.EqualFold(user.Identifier, identifier) || strings.EqualFold(user.EmailAddress, identifier) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (server *Server) refreshUserPermissions(w http.ResponseWriter, r *http.Request, params []string) {
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (server *Server) getCapacities(w http.ResponseWriter, r *http.Request, params []string) {
+	writeJSON(w, http.StatusOK, powerbiapi.GetCapacitiesResponse{
+		Value: append([]powerbiapi.GetCapacitiesResponseItem{}, server.capacities...),
+	})
+}
+
+func (server *Server) assignToCapacity(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+	var request powerbiapi.GroupAssignToCapacityRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.CapacityID == unassignedCapacityID {
+		group.CapacityID = ""
+		writeJSON(w, http.StatusOK, nil)
+		return
+	}
+	for _, capacity := range server.capacities {
+		if strings.EqualFold(capacity.ID, request.CapacityID) {
+			group.CapacityID = capacity.ID
+			writeJSON(w, http.StatusOK, nil)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "CapacityNotFound", fmt.Sprintf("Capacity %s not found", request.CapacityID))
+}
@@ -0,0 +1,195 @@
+package powerbiapitest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+)
+
+func (server *Server) postImport(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+
+	query := r.URL.Query()
+	name := query.Get("datasetDisplayName")
+	nameConflict := query.Get("nameConflict")
+	skipReport := strings.EqualFold(query.Get("skipReport"), "true")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "datasetDisplayName is required")
+		return
+	}
+
+	data, err := readMultipartFile(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+	contents, err := readPbix(data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "RequestedFileIsEncryptedOrCorrupted", err.Error())
+		return
+	}
+
+	var existing *pbixImport
+	for _, pbixImport := range group.Imports {
+		if pbixImport.Name == name {
+			existing = pbixImport
+		}
+	}
+	switch {
+	case existing != nil && nameConflict == "Abort":
+		writeError(w, http.StatusConflict, "DuplicatePackageNameError", fmt.Sprintf("Import %s already exists", name))
+		return
+	case existing == nil && nameConflict == "Overwrite":
+		writeError(w, http.StatusBadRequest, "DuplicatePackageNotFoundError", fmt.Sprintf("Import %s does not exist to overwrite", name))
+		return
+	case existing != nil && nameConflict != "Overwrite" && nameConflict != "CreateOrOverwrite":
+		existing = nil
+	}
+
+	if !contents.HasDataModel && !skipReport && group.findDataset(contents.ConnectedDatasetID) == nil {
+		writeError(w, http.StatusBadRequest, "ImportUnsupportedOptionError", fmt.Sprintf("Report is connected to dataset %s which does not exist in the workspace", contents.ConnectedDatasetID))
+		return
+	}
+
+	now := time.Now().UTC()
+	imported := existing
+	if imported == nil {
+		imported = &pbixImport{
+			Name:            name,
+			CreatedDateTime: now,
+		}
+		group.Imports = append(group.Imports, imported)
+	}
+	imported.ID = server.newID()
+	imported.UpdatedDateTime = now
+	imported.polls = 0
+
+	reportDatasetID := contents.ConnectedDatasetID
+	if contents.HasDataModel {
+		importedDataset := group.findDataset(imported.DatasetID)
+		if importedDataset == nil {
+			importedDataset = &dataset{
+				ID:              server.newID(),
+				RefreshSchedule: defaultRefreshSchedule(),
+			}
+			group.Datasets = append(group.Datasets, importedDataset)
+		}
+		importedDataset.Name = name
+		importedDataset.Parameters = contents.Parameters
+		importedDataset.Datasources = nil
+		for _, datasource := range contents.Datasources {
+			datasource.DatasourceID = server.newID()
+			datasource.GatewayID = server.newID()
+			importedDataset.Datasources = append(importedDataset.Datasources, datasource)
+		}
+		imported.DatasetID = importedDataset.ID
+		reportDatasetID = importedDataset.ID
+	}
+
+	if !skipReport {
+		importedReport := group.findReport(imported.ReportID)
+		if importedReport == nil {
+			importedReport = &report{
+				ID: server.newID(),
+			}
+			group.Reports = append(group.Reports, importedReport)
+		}
+		importedReport.Name = name
+		importedReport.DatasetID = reportDatasetID
+		imported.ReportID = importedReport.ID
+	}
+
+	writeJSON(w, http.StatusAccepted, powerbiapi.PostImportInGroupResponse{
+		ID: imported.ID,
+	})
+}
+
+// readMultipartFile reads the first file in a multipart request
+func readMultipartFile(r *http.Request) ([]byte, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("Expected a multipart request: %v", err)
+	}
+	part, err := reader.NextPart()
+	if err != nil {
+		return nil, fmt.Errorf("Expected a file in the multipart request: %v", err)
+	}
+	defer part.Close()
+	return ioutil.ReadAll(part)
+}
+
+func (group *group) toImportResponse(pbixImport *pbixImport, importState string) powerbiapi.GetImportInGroupResponse {
+	response := powerbiapi.GetImportInGroupResponse{
+		ID:              pbixImport.ID,
+		ImportState:     importState,
+		CreatedDateTime: pbixImport.CreatedDateTime,
+		UpdatedDateTime: pbixImport.UpdatedDateTime,
+		Name:            pbixImport.Name,
+		ConnectionType:  "import",
+		Source:          "Upload",
+		Datasets:        []powerbiapi.GetImportInGroupResponseDataset{},
+		Reports:         []powerbiapi.GetImportInGroupResponseReport{},
+	}
+	if dataset := group.findDataset(pbixImport.DatasetID); dataset != nil {
+		response.Datasets = append(response.Datasets, powerbiapi.GetImportInGroupResponseDataset{
+			ID:   dataset.ID,
+			Name: dataset.Name,
+		})
+	}
+	if report := group.findReport(pbixImport.ReportID); report != nil {
+		response.Reports = append(response.Reports, powerbiapi.GetImportInGroupResponseReport{
+			ID:         report.ID,
+			ReportType: "PowerBIReport",
+			Name:       report.Name,
+		})
+	}
+	return response
+}
+
+func (server *Server) getImports(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+
+	items := []powerbiapi.GetImportInGroupResponse{}
+	for _, pbixImport := range group.Imports {
+		importState := "Publishing"
+		if pbixImport.polls >= server.PublishingPolls {
+			importState = "Succeeded"
+		}
+		items = append(items, group.toImportResponse(pbixImport, importState))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"value": items,
+	})
+}
+
+// getImport reports the import as Publishing for the first PublishingPolls requests, then as Succeeded
+func (server *Server) getImport(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+
+	for _, pbixImport := range group.Imports {
+		if pbixImport.ID != params[1] {
+			continue
+		}
+		importState := "Succeeded"
+		if pbixImport.polls < server.PublishingPolls {
+			importState = "Publishing"
+			pbixImport.polls++
+		}
+		writeJSON(w, http.StatusOK, group.toImportResponse(pbixImport, importState))
+		return
+	}
+	writeError(w, http.StatusNotFound, "PowerBIEntityNotFound", fmt.Sprintf("Import %s not found", params[1]))
+}
@@ -0,0 +1,190 @@
+package powerbiapitest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+)
+
+// pbixContents is what the fake understands of a PBIX file
+type pbixContents struct {
+	// HasDataModel is true if importing the PBIX creates a dataset
+	HasDataModel bool
+	// ConnectedDatasetID is the existing dataset a report only PBIX is connected to
+	ConnectedDatasetID string
+	Parameters         []powerbiapi.GetParametersInGroupResponseItem
+	Datasources        []powerbiapi.GetDatasourcesInGroupResponseItem
+}
+
+var (
+	parameterRegex       = regexp.MustCompile(`shared\s+(\w+)\s*=\s*"([^"]*)"\s*meta\s*\[([^\]]*)\]`)
+	parameterTypeRegex   = regexp.MustCompile(`Type\s*=\s*"(\w+)"`)
+	odataDatasourceRegex = regexp.MustCompile(`OData\.Feed\(\s*"([^"]+)"`)
+	webDatasourceRegex   = regexp.MustCompile(`Web\.Contents\(\s*"([^"]+)"`)
+	sqlDatasourceRegex   = regexp.MustCompile(`Sql\.Databases?\(\s*"([^"]+)"(?:\s*,\s*"([^"]+)")?`)
+)
+
+// readPbix reads the dataset, connection, parameters and datasources from a PBIX file
+func readPbix(data []byte) (*pbixContents, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("File is not a valid PBIX: %v", err)
+	}
+
+	contents := &pbixContents{}
+	for _, file := range zipReader.File {
+		switch file.Name {
+		case "DataModel":
+			contents.HasDataModel = true
+		case "Connections":
+			data, err := readZipFile(file)
+			if err != nil {
+				return nil, err
+			}
+			contents.ConnectedDatasetID = readConnectedDatasetID(data)
+		case "DataMashup":
+			data, err := readZipFile(file)
+			if err != nil {
+				return nil, err
+			}
+			formulas, err := readMashupFormulas(data)
+			if err != nil {
+				return nil, err
+			}
+			contents.Parameters = readParameters(formulas)
+			contents.Datasources = readDatasources(formulas)
+		}
+	}
+	return contents, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+func readConnectedDatasetID(data []byte) string {
+	var connections struct {
+		Connections []struct {
+			PbiModelDatabaseName string
+		}
+	}
+	json.Unmarshal(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), &connections)
+	for _, connection := range connections.Connections {
+		if connection.PbiModelDatabaseName != "" {
+			return connection.PbiModelDatabaseName
+		}
+	}
+	return ""
+}
+
+// readMashupFormulas reads the M formulas out of a DataMashup. The mashup starts with a 4 byte version
+// and 4 byte length, followed by a zip package of that length containing the formulas
+func readMashupFormulas(data []byte) (string, error) {
+	if len(data) < 8 {
+		return "", fmt.Errorf("DataMashup is too short")
+	}
+	length := int(binary.LittleEndian.Uint32(data[4:8]))
+	if 8+length > len(data) {
+		return "", fmt.Errorf("DataMashup package length %d exceeds the size of the mashup", length)
+	}
+
+	packageData := data[8 : 8+length]
+	zipReader, err := zip.NewReader(bytes.NewReader(packageData), int64(len(packageData)))
+	if err != nil {
+		return "", fmt.Errorf("DataMashup package is not valid: %v", err)
+	}
+	for _, file := range zipReader.File {
+		if file.Name == "Formulas/Section1.m" {
+			formulas, err := readZipFile(file)
+			if err != nil {
+				return "", err
+			}
+			return string(bytes.TrimPrefix(formulas, []byte("\xef\xbb\xbf"))), nil
+		}
+	}
+	return "", nil
+}
+
+func readParameters(formulas string) []powerbiapi.GetParametersInGroupResponseItem {
+	parameters := []powerbiapi.GetParametersInGroupResponseItem{}
+	for _, match := range parameterRegex.FindAllStringSubmatch(formulas, -1) {
+		meta := match[3]
+		if !strings.Contains(meta, "IsParameterQuery=true") {
+			continue
+		}
+		parameterType := "Text"
+		if typeMatch := parameterTypeRegex.FindStringSubmatch(meta); typeMatch != nil {
+			parameterType = typeMatch[1]
+		}
+		parameters = append(parameters, powerbiapi.GetParametersInGroupResponseItem{
+			Name:         match[1],
+			Type:         parameterType,
+			IsRequired:   strings.Contains(meta, "IsParameterQueryRequired=true"),
+			CurrentValue: match[2],
+		})
+	}
+	return parameters
+}
+
+func readDatasources(formulas string) []powerbiapi.GetDatasourcesInGroupResponseItem {
+	datasources := []powerbiapi.GetDatasourcesInGroupResponseItem{}
+	add := func(datasourceType string, connectionDetails powerbiapi.GetDatasourcesInGroupResponseItemConnectionDetails) {
+		for _, existing := range datasources {
+			if existing.DatasourceType == datasourceType && equalConnectionDetails(existing.ConnectionDetails, connectionDetails) {
+				return
+			}
+		}
+		datasources = append(datasources, powerbiapi.GetDatasourcesInGroupResponseItem{
+			DatasourceType:    datasourceType,
+			ConnectionDetails: connectionDetails,
+		})
+	}
+
+	for _, match := range odataDatasourceRegex.FindAllStringSubmatch(formulas, -1) {
+		add("OData", powerbiapi.GetDatasourcesInGroupResponseItemConnectionDetails{
+			URL: stringPtr(strings.TrimRight(match[1], "/")),
+		})
+	}
+	for _, match := range webDatasourceRegex.FindAllStringSubmatch(formulas, -1) {
+		add("Web", powerbiapi.GetDatasourcesInGroupResponseItemConnectionDetails{
+			URL: stringPtr(match[1]),
+		})
+	}
+	for _, match := range sqlDatasourceRegex.FindAllStringSubmatch(formulas, -1) {
+		connectionDetails := powerbiapi.GetDatasourcesInGroupResponseItemConnectionDetails{
+			Server: stringPtr(match[1]),
+		}
+		if match[2] != "" {
+			connectionDetails.Database = stringPtr(match[2])
+		}
+		add("Sql", connectionDetails)
+	}
+	return datasources
+}
+
+func equalConnectionDetails(a powerbiapi.GetDatasourcesInGroupResponseItemConnectionDetails, b powerbiapi.GetDatasourcesInGroupResponseItemConnectionDetails) bool {
+	return equalStringPtr(a.URL, b.URL) && equalStringPtr(a.Server, b.Server) && equalStringPtr(a.Database, b.Database)
+}
+
+func equalStringPtr(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
@@ -0,0 +1,99 @@
+package powerbiapitest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+)
+
+// profileIDHeader is the header a service principal sends to make requests as one of its profiles
+const profileIDHeader = "X-PowerBI-Profile-Id"
+
+// requestProfileID returns the profile the request is made as, or an empty string for the service principal itself
+func requestProfileID(r *http.Request) string {
+	return r.Header.Get(profileIDHeader)
+}
+
+// findProfile returns the index of the profile, or -1 if it does not exist
+func (server *Server) findProfile(profileID string) int {
+	for i, profile := range server.profiles {
+		if strings.EqualFold(profile.ID, profileID) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (server *Server) createProfile(w http.ResponseWriter, r *http.Request, params []string) {
+	var request powerbiapi.CreateProfileRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	for _, existing := range server.profiles {
+		if strings.EqualFold(existing.DisplayName, request.DisplayName) {
+			writeError(w, http.StatusConflict, "PowerBIEntityAlreadyExists", fmt.Sprintf("Profile %s already exists", request.DisplayName))
+			return
+		}
+	}
+
+	profile := &powerbiapi.GetProfileResponse{
+		ID:          server.newID(),
+		DisplayName: request.DisplayName,
+	}
+	server.profiles = append(server.profiles, profile)
+
+	writeJSON(w, http.StatusCreated, powerbiapi.CreateProfileResponse{
+		ID:          profile.ID,
+		DisplayName: profile.DisplayName,
+	})
+}
+
+func (server *Server) getProfiles(w http.ResponseWriter, r *http.Request, params []string) {
+	items := []powerbiapi.GetProfilesResponseItem{}
+	for _, profile := range server.profiles {
+		items = append(items, powerbiapi.GetProfilesResponseItem{
+			ID:          profile.ID,
+			DisplayName: profile.DisplayName,
+		})
+	}
+
+	start, end := pageBounds(r.URL.Query(), len(items))
+	writeJSON(w, http.StatusOK, powerbiapi.GetProfilesResponse{
+		Value: items[start:end],
+	})
+}
+
+func (server *Server) getProfile(w http.ResponseWriter, r *http.Request, params []string) {
+	index := server.findProfile(params[0])
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "PowerBIEntityNotFound", fmt.Sprintf("Profile %s not found", params[0]))
+		return
+	}
+	writeJSON(w, http.StatusOK, server.profiles[index])
+}
+
+func (server *Server) updateProfile(w http.ResponseWriter, r *http.Request, params []string) {
+	index := server.findProfile(params[0])
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "PowerBIEntityNotFound", fmt.Sprintf("Profile %s not found", params[0]))
+		return
+	}
+	var request powerbiapi.UpdateProfileRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	server.profiles[index].DisplayName = request.DisplayName
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (server *Server) deleteProfile(w http.ResponseWriter, r *http.Request, params []string) {
+	index := server.findProfile(params[0])
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "PowerBIEntityNotFound", fmt.Sprintf("Profile %s not found", params[0]))
+		return
+	}
+	server.profiles = append(server.profiles[:index], server.profiles[index+1:]...)
+	writeJSON(w, http.StatusOK, nil)
+}
@@ -0,0 +1,95 @@
+package powerbiapitest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+)
+
+func (server *Server) reportURL(group *group, report *report) string {
+	return fmt.Sprintf("%s/groups/%s/reports/%s", server.URL, group.ID, report.ID)
+}
+
+func (server *Server) findGroupAndReport(w http.ResponseWriter, groupID string, reportID string) (*group, *report) {
+	group := server.findGroup(w, groupID)
+	if group == nil {
+		return nil, nil
+	}
+	report := group.findReport(reportID)
+	if report == nil {
+		writeError(w, http.StatusNotFound, "PowerBIEntityNotFound", fmt.Sprintf("Report %s not found", reportID))
+		return nil, nil
+	}
+	return group, report
+}
+
+func (server *Server) getReports(w http.ResponseWriter, r *http.Request, params []string) {
+	group := server.findGroup(w, params[0])
+	if group == nil {
+		return
+	}
+
+	items := []powerbiapi.GetReportsInGroupResponseItem{}
+	for _, report := range group.Reports {
+		items = append(items, powerbiapi.GetReportsInGroupResponseItem{
+			ID:        report.ID,
+			Name:      report.Name,
+			DatasetID: report.DatasetID,
+			WebURL:    server.reportURL(group, report),
+			EmbedURL:  server.reportURL(group, report) + "/embed",
+		})
+	}
+	writeJSON(w, http.StatusOK, powerbiapi.GetReportsInGroupResponse{
+		Value: items,
+	})
+}
+
+func (server *Server) getReport(w http.ResponseWriter, r *http.Request, params []string) {
+	group, report := server.findGroupAndReport(w, params[0], params[1])
+	if report == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, powerbiapi.GetReportInGroupResponse{
+		ID:        report.ID,
+		Name:      report.Name,
+		DatasetID: report.DatasetID,
+		WebURL:    server.reportURL(group, report),
+		EmbedURL:  server.reportURL(group, report) + "/embed",
+	})
+}
+
+func (server *Server) deleteReport(w http.ResponseWriter, r *http.Request, params []string) {
+	group, deleted := server.findGroupAndReport(w, params[0], params[1])
+	if deleted == nil {
+		return
+	}
+
+	reports := group.Reports[:0]
+	for _, report := range group.Reports {
+		if report != deleted {
+			reports = append(reports, report)
+		}
+	}
+	group.Reports = reports
+
+	group.removeOrphanedImports()
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (server *Server) rebindReport(w http.ResponseWriter, r *http.Request, params []string) {
+	group, report := server.findGroupAndReport(w, params[0], params[1])
+	if report == nil {
+		return
+	}
+	var request powerbiapi.RebindReportInGroupRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	if group.findDataset(request.DatasetID) == nil {
+		writeError(w, http.StatusNotFound, "ItemNotFound", fmt.Sprintf("Dataset %s is not found", request.DatasetID))
+		return
+	}
+	report.DatasetID = request.DatasetID
+	writeJSON(w, http.StatusOK, nil)
+}
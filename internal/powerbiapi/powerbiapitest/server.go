@@ -0,0 +1,348 @@
+// Package powerbiapitest provides an in-memory fake of the Power BI REST API so the provider can be tested
+// without a Power BI tenant
+package powerbiapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+)
+
+// unassignedCapacityID is the capacity ID used to move a workspace back to shared capacity
+const unassignedCapacityID = "00000000-0000-0000-0000-000000000000"
+
+// Server is a fake Power BI service. It serves the Azure Active Directory token endpoint and the
+// Power BI REST API from the same URL, so the URL can be used as both the API and login URL
+type Server struct {
+	*httptest.Server
+
+	// PublishingPolls is how many times an import reports it is Publishing before it has Succeeded
+	PublishingPolls int
+
+	mux         sync.Mutex
+	nextID      int
+	groups      []*group
+	myWorkspace *group
+	capacities  []powerbiapi.GetCapacitiesResponseItem
+	profiles    []*powerbiapi.GetProfileResponse
+}
+
+type group struct {
+	ID          string
+	Name        string
+	Description string
+	CapacityID  string
+	ProfileID   string
+	Users       []powerbiapi.GetGroupUsersResponseItem
+	Imports     []*pbixImport
+	Datasets    []*dataset
+	Reports     []*report
+}
+
+type pbixImport struct {
+	ID              string
+	Name            string
+	CreatedDateTime time.Time
+	UpdatedDateTime time.Time
+	DatasetID       string
+	ReportID        string
+	polls           int
+}
+
+type dataset struct {
+	ID                string
+	Name              string
+	AddRowsAPIEnabled bool
+	Parameters        []powerbiapi.GetParametersInGroupResponseItem
+	Datasources       []powerbiapi.GetDatasourcesInGroupResponseItem
+	RefreshSchedule   powerbiapi.GetRefreshScheduleInGroupResponse
+	Tables            []*table
+}
+
+type table struct {
+	Name    string
+	Columns []powerbiapi.PutTableInGroupRequestTableColumn
+	Rows    []map[string]interface{}
+}
+
+type report struct {
+	ID        string
+	Name      string
+	DatasetID string
+}
+
+// NewServer starts a fake Power BI service. The caller should call Close when finished
+func NewServer() *Server {
+	server := &Server{
+		PublishingPolls: 1,
+		myWorkspace: &group{
+			ID:   powerbiapi.MyWorkspaceID,
+			Name: "My workspace",
+		},
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	return server
+}
+
+// AddCapacity adds a capacity that workspaces can be assigned to and returns its ID
+func (server *Server) AddCapacity(displayName string) string {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+
+	id := server.newID()
+	server.capacities = append(server.capacities, powerbiapi.GetCapacitiesResponseItem{
+		ID:                      id,
+		DisplayName:             displayName,
+		SKU:                     "A1",
+		State:                   "Active",
+		Region:                  "West US",
+		CapacityUserAccessRight: "Admin",
+	})
+	return id
+}
+
+// newID generates a unique GUID formatted ID
+func (server *Server) newID() string {
+	server.nextID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", server.nextID)
+}
+
+// route is a request handler for a method and path. Path segments that are `*` capture the segment as a parameter
+type route struct {
+	method  string
+	path    string
+	handler func(server *Server, w http.ResponseWriter, r *http.Request, params []string)
+}
+
+var routes = []route{
+	{"POST", "/v1.0/myorg/groups", (*Server).createGroup},
+	{"GET", "/v1.0/myorg/groups", (*Server).getGroups},
+	{"DELETE", "/v1.0/myorg/groups/*", (*Server).deleteGroup},
+	{"PATCH", "/v1.0/myorg/groups/*", (*Server).updateGroup},
+	{"GET", "/v1.0/myorg/admin/groups/*", (*Server).getGroupAsAdmin},
+	{"PATCH", "/v1.0/myorg/admin/groups/*", (*Server).updateGroupAsAdmin},
+	{"GET", "/v1.0/myorg/groups/*/users", (*Server).getGroupUsers},
+	{"POST", "/v1.0/myorg/groups/*/users", (*Server).addGroupUser},
+	{"PUT", "/v1.0/myorg/groups/*/users", (*Server).updateGroupUser},
+	{"DELETE", "/v1.0/myorg/groups/*/users/*", (*Server).deleteGroupUser},
+	{"POST", "/v1.0/myorg/RefreshUserPermissions", (*Server).refreshUserPermissions},
+	{"GET", "/v1.0/myorg/capacities", (*Server).getCapacities},
+	{"POST", "/v1.0/myorg/groups/*/AssignToCapacity", (*Server).assignToCapacity},
+	{"POST", "/v1.0/myorg/groups/*/imports", (*Server).postImport},
+	{"GET", "/v1.0/myorg/groups/*/imports", (*Server).getImports},
+	{"GET", "/v1.0/myorg/groups/*/imports/*", (*Server).getImport},
+	{"POST", "/v1.0/myorg/groups/*/datasets", (*Server).postDataset},
+	{"GET", "/v1.0/myorg/groups/*/datasets", (*Server).getDatasets},
+	{"GET", "/v1.0/myorg/groups/*/datasets/*", (*Server).getDataset},
+	{"DELETE", "/v1.0/myorg/groups/*/datasets/*", (*Server).deleteDataset},
+	{"GET", "/v1.0/myorg/groups/*/datasets/*/parameters", (*Server).getParameters},
+	{"POST", "/v1.0/myorg/groups/*/datasets/*/Default.UpdateParameters", (*Server).updateParameters},
+	{"GET", "/v1.0/myorg/groups/*/datasets/*/datasources", (*Server).getDatasources},
+	{"POST", "/v1.0/myorg/groups/*/datasets/*/Default.UpdateDatasources", (*Server).updateDatasources},
+	{"GET", "/v1.0/myorg/groups/*/datasets/*/refreshSchedule", (*Server).getRefreshSchedule},
+	{"PATCH", "/v1.0/myorg/groups/*/datasets/*/refreshSchedule", (*Server).updateRefreshSchedule},
+	{"GET", "/v1.0/myorg/datasets/*/tables", (*Server).getTables},
+	{"PUT", "/v1.0/myorg/groups/*/datasets/*/tables/*", (*Server).putTable},
+	{"POST", "/v1.0/myorg/groups/*/datasets/*/tables/*/rows", (*Server).postRows},
+	{"GET", "/v1.0/myorg/groups/*/reports", (*Server).getReports},
+	{"GET", "/v1.0/myorg/groups/*/reports/*", (*Server).getReport},
+	{"DELETE", "/v1.0/myorg/groups/*/reports/*", (*Server).deleteReport},
+	{"POST", "/v1.0/myorg/groups/*/reports/*/Rebind", (*Server).rebindReport},
+	{"POST", "/v1.0/myorg/profiles", (*Server).createProfile},
+	{"GET", "/v1.0/myorg/profiles", (*Server).getProfiles},
+	{"GET", "/v1.0/myorg/profiles/*", (*Server).getProfile},
+	{"PATCH", "/v1.0/myorg/profiles/*", (*Server).updateProfile},
+	{"DELETE", "/v1.0/myorg/profiles/*", (*Server).deleteProfile},
+}
+
+func init() {
+	routes = append(routes, myWorkspaceRoutes(routes)...)
+}
+
+// myWorkspaceRoutes returns the routes of the content within a workspace addressed without /groups/{groupID},
+// which is how the content of My workspace is addressed
+func myWorkspaceRoutes(groupRoutes []route) []route {
+	const groupPrefix = "/v1.0/myorg/groups/*/"
+	var myRoutes []route
+	for _, groupRoute := range groupRoutes {
+		if !strings.HasPrefix(groupRoute.path, groupPrefix) {
+			continue
+		}
+		path := "/v1.0/myorg/" + strings.TrimPrefix(groupRoute.path, groupPrefix)
+		if !strings.HasPrefix(path, "/v1.0/myorg/imports") && !strings.HasPrefix(path, "/v1.0/myorg/datasets") && !strings.HasPrefix(path, "/v1.0/myorg/reports") {
+			continue
+		}
+		handler := groupRoute.handler
+		myRoutes = append(myRoutes, route{groupRoute.method, path, func(server *Server, w http.ResponseWriter, r *http.Request, params []string) {
+			handler(server, w, r, append([]string{powerbiapi.MyWorkspaceID}, params...))
+		}})
+	}
+	return myRoutes
+}
+
+var tokenPathRegex = regexp.MustCompile(`^/[^/]+/oauth2/v2.0/token$`)
+
+func (server *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("RequestId", server.requestID())
+
+	if r.Method == "POST" && tokenPathRegex.MatchString(r.URL.Path) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"token_type":   "Bearer",
+			"access_token": "fake-access-token",
+			"expires_in":   3600,
+		})
+		return
+	}
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeError(w, http.StatusUnauthorized, "PowerBINotAuthorizedException", "Missing bearer token")
+		return
+	}
+
+	segments := splitPath(r.URL.EscapedPath())
+	pathMatched := false
+	for _, route := range routes {
+		params, ok := matchPath(splitPath(route.path), segments)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if route.method != r.Method {
+			continue
+		}
+
+		server.mux.Lock()
+		defer server.mux.Unlock()
+		if profileID := requestProfileID(r); profileID != "" && server.findProfile(profileID) < 0 {
+			writeError(w, http.StatusUnauthorized, "PowerBINotAuthorizedException", fmt.Sprintf("Profile %s not found", profileID))
+			return
+		}
+		route.handler(server, w, r, params)
+		return
+	}
+
+	if pathMatched {
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", fmt.Sprintf("%s is not supported on %s", r.Method, r.URL.Path))
+		return
+	}
+	writeError(w, http.StatusNotFound, "EndpointNotFound", fmt.Sprintf("No fake implementation of %s %s", r.Method, r.URL.Path))
+}
+
+func (server *Server) requestID() string {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+	return server.newID()
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// matchPath compares the path segments against the pattern segments returning the captured parameters
+func matchPath(pattern []string, segments []string) ([]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	var params []string
+	for i := range pattern {
+		if pattern[i] == "*" {
+			param, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return nil, false
+			}
+			params = append(params, param)
+		} else if !strings.EqualFold(pattern[i], segments[i]) {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	if body != nil {
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code string, message string) {
+	writeJSON(w, statusCode, powerbiapi.ErrorResponse{
+		Error: powerbiapi.ErrorBody{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
+
+// readJSON reads the request body, if it cannot be read a bad request is written and false returned
+func readJSON(w http.ResponseWriter, r *http.Request, body interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Unable to read request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func (server *Server) findGroup(w http.ResponseWriter, groupID string) *group {
+	if groupID == powerbiapi.MyWorkspaceID {
+		return server.myWorkspace
+	}
+	for _, group := range server.groups {
+		if group.ID == groupID {
+			return group
+		}
+	}
+	writeError(w, http.StatusNotFound, "PowerBIEntityNotFound", fmt.Sprintf("Workspace %s not found", groupID))
+	return nil
+}
+
+func (group *group) findDataset(datasetID string) *dataset {
+	for _, dataset := range group.Datasets {
+		if dataset.ID == datasetID {
+			return dataset
+		}
+	}
+	return nil
+}
+
+func (group *group) findReport(reportID string) *report {
+	for _, report := range group.Reports {
+		if report.ID == reportID {
+			return report
+		}
+	}
+	return nil
+}
+
+func (server *Server) findGroupAndDataset(w http.ResponseWriter, groupID string, datasetID string) (*group, *dataset) {
+	group := server.findGroup(w, groupID)
+	if group == nil {
+		return nil, nil
+	}
+	dataset := group.findDataset(datasetID)
+	if dataset == nil {
+		writeError(w, http.StatusNotFound, "ItemNotFound", fmt.Sprintf("Dataset %s is not found", datasetID))
+		return nil, nil
+	}
+	return group, dataset
+}
+
+// removeOrphanedImports removes imports whose dataset and report have both been deleted
+func (group *group) removeOrphanedImports() {
+	imports := group.Imports[:0]
+	for _, pbixImport := range group.Imports {
+		if group.findDataset(pbixImport.DatasetID) != nil || group.findReport(pbixImport.ReportID) != nil {
+			imports = append(imports, pbixImport)
+		}
+	}
+	group.Imports = imports
+}
@@ -0,0 +1,243 @@
+package powerbiapitest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+)
+
+func newTestClient(t *testing.T, server *Server) *powerbiapi.Client {
+	client, err := powerbiapi.NewClientWithClientCredentialAuth(powerbiapi.ClientOptions{
+		Endpoints: powerbiapi.Endpoints{APIURL: server.URL, LoginURL: server.URL},
+	}, "tenant", "client", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestServer_importPublishesThenSucceeds(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	group, err := client.CreateGroup(powerbiapi.CreateGroupRequest{Name: "Workspace"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open("../../powerbi/resource_pbix_test_sample1.pbix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	imported, err := client.PostImportInGroup(group.ID, "Sample", "CreateOrOverwrite", false, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publishing, err := client.GetImportInGroup(group.ID, imported.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publishing.ImportState != "Publishing" {
+		t.Fatalf("expected first poll to be Publishing but was %s", publishing.ImportState)
+	}
+
+	succeeded, err := client.GetImportInGroup(group.ID, imported.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if succeeded.ImportState != "Succeeded" || len(succeeded.Datasets) != 1 || len(succeeded.Reports) != 1 {
+		t.Fatalf("expected a succeeded import with a dataset and report but was %+v", succeeded)
+	}
+
+	parameters, err := client.GetParametersInGroup(group.ID, succeeded.Datasets[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parameters.Value) != 2 || parameters.Value[0].Name != "ParamOne" || parameters.Value[0].CurrentValue != "ParamOneValue" {
+		t.Fatalf("expected parameters from the PBIX mashup but was %+v", parameters.Value)
+	}
+
+	datasources, err := client.GetDatasourcesInGroup(group.ID, succeeded.Datasets[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(datasources.Value) != 1 || *datasources.Value[0].ConnectionDetails.URL != "https://services.odata.org/V3/OData/OData.svc" {
+		t.Fatalf("expected the OData datasource from the PBIX mashup but was %+v", datasources.Value)
+	}
+}
+
+func TestServer_deletingDatasetRemovesReportsAndImport(t *testing.T) {
+	server := NewServer()
+	server.PublishingPolls = 0
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	group, err := client.CreateGroup(powerbiapi.CreateGroupRequest{Name: "Workspace"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open("../../powerbi/resource_pbix_test_sample1.pbix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	imported, err := client.PostImportInGroup(group.ID, "Sample", "CreateOrOverwrite", false, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	importResponse, err := client.GetImportInGroup(group.ID, imported.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.DeleteDatasetInGroup(group.ID, importResponse.Datasets[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetReportInGroup(group.ID, importResponse.Reports[0].ID); !powerbiapi.IsNotFoundError(err) {
+		t.Fatalf("expected report to be deleted with its dataset but got %v", err)
+	}
+	if _, err := client.GetImportInGroup(group.ID, imported.ID); !powerbiapi.IsNotFoundError(err) {
+		t.Fatalf("expected import to be removed once its dataset and report were deleted but got %v", err)
+	}
+}
+
+func TestServer_descriptionsAreOnlyAvailableThroughAdminAPIs(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	group, err := client.CreateGroup(powerbiapi.CreateGroupRequest{Name: "Workspace"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.UpdateGroup(group.ID, powerbiapi.UpdateGroupRequest{Name: "Renamed"}); err != nil {
+		t.Fatal(err)
+	}
+	description := "Described"
+	if err := client.UpdateGroupAsAdmin(group.ID, powerbiapi.UpdateGroupAsAdminRequest{Name: "Renamed", Description: &description}); err != nil {
+		t.Fatal(err)
+	}
+
+	adminGroup, err := client.GetGroupAsAdmin(group.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if adminGroup.Name != "Renamed" || adminGroup.Description != "Described" {
+		t.Fatalf("expected the admin API to return the name and description but was %+v", adminGroup)
+	}
+}
+
+func TestServer_getGroupsFilters(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	for _, name := range []string{"First", "Second"} {
+		if _, err := client.CreateGroup(powerbiapi.CreateGroupRequest{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	group, err := client.GetGroupByName("Second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group == nil || group.Name != "Second" {
+		t.Fatalf("expected to find group Second but was %+v", group)
+	}
+
+	missing, err := client.GetGroupByName("Third")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing != nil {
+		t.Fatalf("expected no group but was %+v", missing)
+	}
+}
+
+func TestServer_profilesOnlySeeTheirWorkspaces(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	profile, err := client.CreateProfile(powerbiapi.CreateProfileRequest{DisplayName: "Tenant"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	profileCtx := powerbiapi.WithProfileID(context.Background(), profile.ID)
+
+	if _, err := client.CreateGroupWithContext(profileCtx, powerbiapi.CreateGroupRequest{Name: "Tenant Workspace"}); err != nil {
+		t.Fatal(err)
+	}
+
+	asProfile, err := client.GetGroupByNameWithContext(profileCtx, "Tenant Workspace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asProfile == nil {
+		t.Fatal("expected the profile to see its workspace")
+	}
+
+	asServicePrincipal, err := client.GetGroupByName("Tenant Workspace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asServicePrincipal != nil {
+		t.Fatalf("expected the service principal not to see the profile workspace but was %+v", asServicePrincipal)
+	}
+
+	if err := client.DeleteProfile(profile.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetGroupsWithContext(profileCtx, "", 0, 0); !powerbiapi.IsForbiddenError(err) {
+		t.Fatalf("expected requests as a deleted profile to be unauthorized but got %v", err)
+	}
+}
+
+func TestServer_myWorkspaceIsAddressedWithoutGroup(t *testing.T) {
+	server := NewServer()
+	server.PublishingPolls = 0
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	file, err := os.Open("../../powerbi/resource_pbix_test_sample1.pbix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	imported, err := client.PostImportInGroup(powerbiapi.MyWorkspaceID, "Sample", "CreateOrOverwrite", false, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	importResponse, err := client.GetImportInGroup("", imported.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datasets, err := client.GetDatasetsInGroup("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(datasets.Value) != 1 || datasets.Value[0].ID != importResponse.Datasets[0].ID {
+		t.Fatalf("expected the imported dataset in My workspace but was %+v", datasets.Value)
+	}
+
+	groups, err := client.GetGroups("", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups.Value) != 0 {
+		t.Fatalf("expected My workspace not to be listed as a group but was %+v", groups.Value)
+	}
+}
@@ -0,0 +1,110 @@
+package powerbiapi
+
+import (
+	"context"
+	"net/url"
+)
+
+// CreateProfileRequest represents the request for the CreateProfile API
+type CreateProfileRequest struct {
+	DisplayName string `json:"displayName"`
+}
+
+// CreateProfileResponse represents the response from the CreateProfile API
+type CreateProfileResponse struct {
+	ID          string
+	DisplayName string
+}
+
+// GetProfileResponse represents the details when getting an individual service principal profile
+type GetProfileResponse struct {
+	ID          string
+	DisplayName string
+}
+
+// GetProfilesResponse represents the response from the GetProfiles API
+type GetProfilesResponse struct {
+	Value []GetProfilesResponseItem
+}
+
+// GetProfilesResponseItem represents an item returned within GetProfilesResponse
+type GetProfilesResponseItem struct {
+	ID          string
+	DisplayName string
+}
+
+// UpdateProfileRequest represents the request for the UpdateProfile API
+type UpdateProfileRequest struct {
+	DisplayName string `json:"displayName"`
+}
+
+// CreateProfile creates a service principal profile
+func (client *Client) CreateProfile(request CreateProfileRequest) (*CreateProfileResponse, error) {
+	return client.CreateProfileWithContext(context.Background(), request)
+}
+
+// CreateProfileWithContext is the same as CreateProfile with the addition of a context to cancel the request
+func (client *Client) CreateProfileWithContext(ctx context.Context, request CreateProfileRequest) (*CreateProfileResponse, error) {
+
+	// profiles belong to the service principal itself, so profile requests are never made as a profile
+	var respObj CreateProfileResponse
+	err := client.doJSON(WithProfileID(ctx, ""), "POST", client.url("/profiles"), &request, &respObj)
+
+	return &respObj, err
+}
+
+// GetProfiles returns the service principal profiles owned by the service principal
+func (client *Client) GetProfiles() (*GetProfilesResponse, error) {
+	return client.GetProfilesWithContext(context.Background())
+}
+
+// GetProfilesWithContext is the same as GetProfiles with the addition of a context to cancel the request
+func (client *Client) GetProfilesWithContext(ctx context.Context) (*GetProfilesResponse, error) {
+
+	var respObj GetProfilesResponse
+	err := client.getAllPages(WithProfileID(ctx, ""), client.url("/profiles"), 0, &respObj.Value)
+
+	return &respObj, err
+}
+
+// GetProfile returns a single service principal profile
+func (client *Client) GetProfile(profileID string) (*GetProfileResponse, error) {
+	return client.GetProfileWithContext(context.Background(), profileID)
+}
+
+// GetProfileWithContext is the same as GetProfile with the addition of a context to cancel the request
+func (client *Client) GetProfileWithContext(ctx context.Context, profileID string) (*GetProfileResponse, error) {
+
+	var respObj GetProfileResponse
+	url := client.url("/profiles/%s", url.PathEscape(profileID))
+	err := client.doJSON(WithProfileID(ctx, ""), "GET", url, nil, &respObj)
+
+	return &respObj, err
+}
+
+// UpdateProfile renames a service principal profile
+func (client *Client) UpdateProfile(profileID string, request UpdateProfileRequest) error {
+	return client.UpdateProfileWithContext(context.Background(), profileID, request)
+}
+
+// UpdateProfileWithContext is the same as UpdateProfile with the addition of a context to cancel the request
+func (client *Client) UpdateProfileWithContext(ctx context.Context, profileID string, request UpdateProfileRequest) error {
+	url := client.url("/profiles/%s", url.PathEscape(profileID))
+	err := client.doJSON(WithProfileID(ctx, ""), "PATCH", url, &request, nil)
+
+	return err
+}
+
+// DeleteProfile deletes a service principal profile
+func (client *Client) DeleteProfile(profileID string) error {
+	return client.DeleteProfileWithContext(context.Background(), profileID)
+}
+
+// DeleteProfileWithContext is the same as DeleteProfile with the addition of a context to cancel the request
+func (client *Client) DeleteProfileWithContext(ctx context.Context, profileID string) error {
+	url := client.url("/profiles/%s", url.PathEscape(profileID))
+	err := client.doJSON(WithProfileID(ctx, ""), "DELETE", url, nil, nil)
+	client.cache.invalidate(groupsCacheKey, capacitiesCacheKey)
+
+	return err
+}
@@ -1,7 +1,7 @@
 package powerbiapi
 
 import (
-	"fmt"
+	"context"
 	"net/url"
 )
 
@@ -86,48 +86,65 @@ type PostRowsInGroupRequest struct {
 
 // PostDatasetInGroup creates a dataset within the specified group.
 func (client *Client) PostDatasetInGroup(groupID string, defaultRetentionPolicy string, request PostDatasetInGroupRequest) (*PostDatasetInGroupResponse, error) {
+	return client.PostDatasetInGroupWithContext(context.Background(), groupID, defaultRetentionPolicy, request)
+}
+
+// PostDatasetInGroupWithContext is the same as PostDatasetInGroup with the addition of a context to cancel the request
+func (client *Client) PostDatasetInGroupWithContext(ctx context.Context, groupID string, defaultRetentionPolicy string, request PostDatasetInGroupRequest) (*PostDatasetInGroupResponse, error) {
 
 	queryParams := url.Values{}
 	if defaultRetentionPolicy != "" {
 		queryParams.Add("defaultRetentionPolicy", defaultRetentionPolicy)
 	}
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets?%s",
-		url.PathEscape(groupID),
+	url := client.groupURL(groupID, "/datasets?%s",
 		queryParams.Encode())
 
 	var respObj PostDatasetInGroupResponse
-	err := client.doJSON("POST", url, &request, &respObj)
+	err := client.doJSON(ctx, "POST", url, &request, &respObj)
 	return &respObj, err
 }
 
 // GetTables gets the tables in a push dataset.
 func (client *Client) GetTables(datasetID string) (*GetTablesResponse, error) {
+	return client.GetTablesWithContext(context.Background(), datasetID)
+}
+
+// GetTablesWithContext is the same as GetTables with the addition of a context to cancel the request
+func (client *Client) GetTablesWithContext(ctx context.Context, datasetID string) (*GetTablesResponse, error) {
 
 	var respObj GetTablesResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/datasets/%s/tables", url.PathEscape(datasetID))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.url("/datasets/%s/tables", url.PathEscape(datasetID))
+	err := client.doJSON(ctx, "GET", url, nil, &respObj)
 
 	return &respObj, err
 }
 
 // PutTableInGroup updates the metadata and schema for the specified table, within the specified dataset, from the specified workspace.
 func (client *Client) PutTableInGroup(groupID string, datasetID string, tableName string, request PutTableInGroupRequest) error {
+	return client.PutTableInGroupWithContext(context.Background(), groupID, datasetID, tableName, request)
+}
+
+// PutTableInGroupWithContext is the same as PutTableInGroup with the addition of a context to cancel the request
+func (client *Client) PutTableInGroupWithContext(ctx context.Context, groupID string, datasetID string, tableName string, request PutTableInGroupRequest) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/tables/%s",
-		url.PathEscape(groupID),
+	url := client.groupURL(groupID, "/datasets/%s/tables/%s",
 		url.PathEscape(datasetID),
 		url.PathEscape(tableName))
 
-	return client.doJSON("PUT", url, &request, nil)
+	return client.doJSON(ctx, "PUT", url, &request, nil)
 }
 
 // PostRowsInGroup posts rows into a table in a dataset in a group.
 func (client *Client) PostRowsInGroup(groupID string, datasetID string, tableName string, request PostRowsInGroupRequest) error {
+	return client.PostRowsInGroupWithContext(context.Background(), groupID, datasetID, tableName, request)
+}
+
+// PostRowsInGroupWithContext is the same as PostRowsInGroup with the addition of a context to cancel the request
+func (client *Client) PostRowsInGroupWithContext(ctx context.Context, groupID string, datasetID string, tableName string, request PostRowsInGroupRequest) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/tables/%s/rows",
-		url.PathEscape(groupID),
+	url := client.groupURL(groupID, "/datasets/%s/tables/%s/rows",
 		url.PathEscape(datasetID),
 		url.PathEscape(tableName))
-	return client.doJSON("POST", url, &request, nil)
+	return client.doJSON(ctx, "POST", url, &request, nil)
 }
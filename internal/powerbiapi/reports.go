@@ -1,7 +1,7 @@
 package powerbiapi
 
 import (
-	"fmt"
+	"context"
 	"net/url"
 )
 
@@ -35,38 +35,58 @@ type GetReportInGroupResponse struct {
 
 // GetReportsInGroup returns a list of reports within the specified group.
 func (client *Client) GetReportsInGroup(groupID string) (*GetReportsInGroupResponse, error) {
+	return client.GetReportsInGroupWithContext(context.Background(), groupID)
+}
+
+// GetReportsInGroupWithContext is the same as GetReportsInGroup with the addition of a context to cancel the request
+func (client *Client) GetReportsInGroupWithContext(ctx context.Context, groupID string) (*GetReportsInGroupResponse, error) {
 
 	var respObj GetReportsInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/reports", url.PathEscape(groupID))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.groupURL(groupID, "/reports")
+	err := client.getAllPages(ctx, url, 0, &respObj.Value)
 
 	return &respObj, err
 }
 
 // GetReportInGroup returns a report that exists within a group
 func (client *Client) GetReportInGroup(groupID string, reportID string) (*GetReportInGroupResponse, error) {
+	return client.GetReportInGroupWithContext(context.Background(), groupID, reportID)
+}
+
+// GetReportInGroupWithContext is the same as GetReportInGroup with the addition of a context to cancel the request
+func (client *Client) GetReportInGroupWithContext(ctx context.Context, groupID string, reportID string) (*GetReportInGroupResponse, error) {
 
 	var respObj GetReportInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/reports/%s", url.PathEscape(groupID), url.PathEscape(reportID))
-	err := client.doJSON("GET", url, nil, &respObj)
+	url := client.groupURL(groupID, "/reports/%s", url.PathEscape(reportID))
+	err := client.doJSON(ctx, "GET", url, nil, &respObj)
 
 	return &respObj, err
 }
 
 // DeleteReportInGroup deletes a report that exists within a group.
 func (client *Client) DeleteReportInGroup(groupID string, reportID string) error {
+	return client.DeleteReportInGroupWithContext(context.Background(), groupID, reportID)
+}
+
+// DeleteReportInGroupWithContext is the same as DeleteReportInGroup with the addition of a context to cancel the request
+func (client *Client) DeleteReportInGroupWithContext(ctx context.Context, groupID string, reportID string) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/reports/%s", url.PathEscape(groupID), url.PathEscape(reportID))
-	err := client.doJSON("DELETE", url, nil, nil)
+	url := client.groupURL(groupID, "/reports/%s", url.PathEscape(reportID))
+	err := client.doJSON(ctx, "DELETE", url, nil, nil)
 
 	return err
 }
 
 // RebindReportInGroup rebinds the specified report from the specified group to the requested dataset.
 func (client *Client) RebindReportInGroup(groupID string, reportID string, request RebindReportInGroupRequest) error {
+	return client.RebindReportInGroupWithContext(context.Background(), groupID, reportID, request)
+}
+
+// RebindReportInGroupWithContext is the same as RebindReportInGroup with the addition of a context to cancel the request
+func (client *Client) RebindReportInGroupWithContext(ctx context.Context, groupID string, reportID string, request RebindReportInGroupRequest) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/reports/%s/Rebind", url.PathEscape(groupID), url.PathEscape(reportID))
-	err := client.doJSON("POST", url, request, nil)
+	url := client.groupURL(groupID, "/reports/%s/Rebind", url.PathEscape(reportID))
+	err := client.doJSON(ctx, "POST", url, request, nil)
 
 	return err
 }
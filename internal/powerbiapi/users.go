@@ -1,8 +1,15 @@
 package powerbiapi
 
+import "context"
+
 //RefreshUserPermissions Refreshes user permissions in Power BI.
 func (client *Client) RefreshUserPermissions() error {
-	err := client.doJSON("POST", "https://api.powerbi.com/v1.0/myorg/RefreshUserPermissions", nil, nil)
+	return client.RefreshUserPermissionsWithContext(context.Background())
+}
+
+// RefreshUserPermissionsWithContext is the same as RefreshUserPermissions with the addition of a context to cancel the request
+func (client *Client) RefreshUserPermissionsWithContext(ctx context.Context) error {
+	err := client.doJSON(ctx, "POST", client.url("/RefreshUserPermissions"), nil, nil)
 
 	return err
 }